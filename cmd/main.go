@@ -1,13 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"kubeguide/internal/app"
+	"kubeguide/internal/config"
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/validation"
 )
 
 func main() {
-	kubeguideApp := app.New()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "validate":
+			os.Exit(runValidate(os.Args[2:]))
+		case "export-schemas":
+			os.Exit(runExportSchemas(os.Args[2:]))
+		}
+	}
+
+	var opts app.Options
+	flag.StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	flag.StringVar(&opts.Context, "context", "", "Kubeconfig context to use (defaults to the current-context)")
+	flag.StringVar(&opts.Namespace, "namespace", "", "Namespace to start in (defaults to the context's namespace)")
+	schemaFlags(flag.CommandLine, &opts.Schemas)
+	flag.Parse()
+
+	kubeguideApp := app.New(opts)
 
 	if err := kubeguideApp.Initialize(); err != nil {
 		fmt.Printf("Error initializing application: %v\n", err)
@@ -18,3 +44,124 @@ func main() {
 		panic(err)
 	}
 }
+
+// Flags choosing the schemas manifests are validated against without a cluster
+func schemaFlags(flags *flag.FlagSet, opts *validation.OfflineOptions) {
+	flags.StringVar(&opts.SchemaDir, "schema-dir", "", "Directory of OpenAPI v3 documents to validate against, optionally with a subdirectory per --kube-version")
+	flags.StringVar(&opts.KubeVersion, "kube-version", "", "Kubernetes version of the schemas, e.g. v1.33: that subdirectory of --schema-dir, or the bundled schemas (bundled versions: "+strings.Join(validation.BundledVersions(), ", ")+")")
+	flags.Func("crd", "CRD manifest to validate custom resources against (repeatable)", func(path string) error {
+		opts.CRDFiles = append(opts.CRDFiles, path)
+		return nil
+	})
+}
+
+// kubeguide validate: check manifests offline, e.g. in CI. Exits with 1 if
+// any manifest has errors, warnings alone don't fail.
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubeguide validate [flags] FILE... (- reads stdin)\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var opts validation.OfflineOptions
+	schemaFlags(flags, &opts)
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	sources, err := validation.OfflineSources(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	// Built-in kinds fall back to the newest bundled schemas
+	if opts.SchemaDir == "" && opts.KubeVersion == "" {
+		if bundled := validation.NewestBundledSchemas(); bundled != nil {
+			sources = append(sources, bundled)
+		}
+	}
+	if len(sources) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no schemas to validate against, pass --schema-dir, --kube-version or --crd")
+		return 2
+	}
+	validator := validation.NewValidator(sources...)
+
+	// House rules from the config file apply here as well
+	cfg, err := config.Load()
+	switch {
+	case errors.Is(err, config.ErrInvalidLintRules):
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	case err == nil:
+		validator.SetLinter(cfg.ValidationLinter())
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		var data []byte
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = 2
+			continue
+		}
+
+		for _, diagnostic := range validator.Validate(context.Background(), data) {
+			location := fmt.Sprintf("%s:%d:%d", path, diagnostic.Line, diagnostic.Column)
+			if diagnostic.Path != "" {
+				fmt.Printf("%s: %s: %s: %s\n", location, diagnostic.Severity, diagnostic.Path, diagnostic.Message)
+			} else {
+				fmt.Printf("%s: %s: %s\n", location, diagnostic.Severity, diagnostic.Message)
+			}
+			if diagnostic.Severity == validation.SeverityError && exitCode == 0 {
+				exitCode = 1
+			}
+		}
+	}
+	return exitCode
+}
+
+// kubeguide export-schemas: save a cluster's OpenAPI v3 documents for
+// offline validation, into a subdirectory named after its version
+func runExportSchemas(args []string) int {
+	flags := flag.NewFlagSet("export-schemas", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubeguide export-schemas [flags] DIR\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	var clientOpts kubernetes.ClientOptions
+	flags.StringVar(&clientOpts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	flags.StringVar(&clientOpts.Context, "context", "", "Kubeconfig context to use (defaults to the current-context)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	kubeClient, err := kubernetes.NewUnifiedClient(clientOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	version, err := kubeClient.DiscoveryClient().ServerVersion()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to get server version: %v\n", err)
+		return 1
+	}
+
+	dir := filepath.Join(flags.Arg(0), fmt.Sprintf("v%s.%s", version.Major, strings.TrimSuffix(version.Minor, "+")))
+	schemas := validation.NewClusterSchemas(kubeClient.DiscoveryClient(), kubeClient.CRDClient(), "")
+	written, err := schemas.Export(context.Background(), dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %d documents to %s\n", written, dir)
+	return 0
+}
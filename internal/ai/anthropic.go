@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"kubeguide/internal/config"
+)
+
+const AnthropicProviderName = "anthropic"
+
+func init() {
+	RegisterProvider(AnthropicProviderName, NewAnthropicProvider)
+}
+
+type AnthropicRequest struct {
+	Model       string        `json:"model"`
+	MaxTokens   int           `json:"max_tokens"`
+	Messages    []ChatMessage `json:"messages"`
+	System      string        `json:"system,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type AnthropicResponse struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Role    string `json:"role"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Model        string `json:"model"`
+	StopReason   string `json:"stop_reason"`
+	StopSequence string `json:"stop_sequence"`
+	Usage        struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Event of a message stream, only the fields text deltas and errors need
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// The Messages API requires max_tokens
+const anthropicDefaultMaxTokens = 1000
+
+// Provider for the Anthropic Messages API
+type AnthropicProvider struct {
+	config *config.AIConfig
+	api    httpAPI
+}
+
+func NewAnthropicProvider(cfg *config.AIConfig, httpClient *http.Client) Provider {
+	header := http.Header{}
+	header.Set("x-api-key", cfg.APIKey)
+	header.Set("anthropic-version", "2023-06-01")
+	return &AnthropicProvider{
+		config: cfg,
+		api:    newHTTPAPI(AnthropicProviderName, cfg.BaseURL, httpClient, header),
+	}
+}
+
+func (p *AnthropicProvider) Name() string {
+	return AnthropicProviderName
+}
+
+func (p *AnthropicProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ListModels: true, RequiresAPIKey: true}
+}
+
+func (p *AnthropicProvider) messagesRequest(req Request) AnthropicRequest {
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = anthropicDefaultMaxTokens
+	}
+	return AnthropicRequest{
+		Model:       modelOf(req, p.config),
+		MaxTokens:   maxTokens,
+		Messages:    req.Messages,
+		System:      req.System,
+		Temperature: req.Temperature,
+	}
+}
+
+func (p *AnthropicProvider) Chat(ctx context.Context, req Request) (string, error) {
+	var anthResp AnthropicResponse
+	if err := p.api.call(ctx, http.MethodPost, "/v1/messages", p.messagesRequest(req), &anthResp); err != nil {
+		return "", err
+	}
+
+	if anthResp.Error != nil {
+		return "", fmt.Errorf("Anthropic API error: %s", anthResp.Error.Message)
+	}
+
+	if len(anthResp.Content) == 0 {
+		return "", fmt.Errorf("no content returned from Anthropic API")
+	}
+
+	return anthResp.Content[0].Text, nil
+}
+
+// Server-sent events, text arrives in content_block_delta events
+func (p *AnthropicProvider) Stream(ctx context.Context, req Request, onText StreamFunc) error {
+	messagesReq := p.messagesRequest(req)
+	messagesReq.Stream = true
+
+	body, err := p.api.do(ctx, http.MethodPost, "/v1/messages", messagesReq)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return readServerSentEvents(body, func(event, data string) (bool, error) {
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return false, fmt.Errorf("failed to unmarshal stream event: %w", err)
+		}
+		switch ev.Type {
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				onText(ev.Delta.Text)
+			}
+		case "error":
+			if ev.Error != nil {
+				return false, fmt.Errorf("Anthropic API error: %s", ev.Error.Message)
+			}
+			return false, fmt.Errorf("Anthropic API error")
+		case "message_stop":
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+func (p *AnthropicProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := p.api.call(ctx, http.MethodGet, "/v1/models?limit=1000", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, model := range resp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Both headers on every request, and no OpenAI-style bearer token
+func assertAnthropicHeaders(t *testing.T, header http.Header) {
+	t.Helper()
+	if got := header.Get("x-api-key"); got != "test-key" {
+		t.Errorf("x-api-key = %q", got)
+	}
+	if got := header.Get("anthropic-version"); got != "2023-06-01" {
+		t.Errorf("anthropic-version = %q", got)
+	}
+	if got := header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none", got)
+	}
+}
+
+func TestAnthropicProviderChat(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"type":"message","role":"assistant","content":[{"type":"text","text":"It's DNS."}],"stop_reason":"end_turn"}`))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	answer, err := provider.Chat(t.Context(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "It's DNS." {
+		t.Errorf("answer = %q", answer)
+	}
+
+	req := received()
+	if req.Method != http.MethodPost || req.Path != "/v1/messages" {
+		t.Errorf("request = %s %s, want POST /v1/messages", req.Method, req.Path)
+	}
+	assertAnthropicHeaders(t, req.Header)
+	// The system prompt is a field of its own, not a message
+	if req.Body["system"] != "You are a test." || req.Body["model"] != "test-model" || req.Body["max_tokens"] != 100.0 {
+		t.Errorf("body = %v", req.Body)
+	}
+	assertMessages(t, bodyMessages(t, req.Body), testRequest.Messages)
+}
+
+func TestAnthropicProviderDefaultMaxTokens(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"content":[{"type":"text","text":"ok"}]}`))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	if _, err := provider.Chat(t.Context(), Request{Messages: testRequest.Messages}); err != nil {
+		t.Fatal(err)
+	}
+	req := received()
+	if req.Body["max_tokens"] != float64(anthropicDefaultMaxTokens) {
+		t.Errorf("max_tokens = %v, want %d", req.Body["max_tokens"], anthropicDefaultMaxTokens)
+	}
+	if _, ok := req.Body["system"]; ok {
+		t.Errorf("empty system prompt sent")
+	}
+}
+
+func TestAnthropicProviderChatErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"error object", `{"type":"error","error":{"type":"invalid_request_error","message":"bad model"}}`, "Anthropic API error: bad model"},
+		{"no content", `{"content":[]}`, "no content returned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, respondJSON(tt.response))
+			_, err := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client()).Chat(t.Context(), testRequest)
+			assertError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAnthropicProviderStream(t *testing.T) {
+	server, received := newTestServer(t, respondStream("text/event-stream",
+		"event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"role\":\"assistant\"}}\n\n",
+		"event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n",
+		"event: ping\ndata: {\"type\":\"ping\"}\n\n",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"It's \"}}\n\n",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"DNS.\"}}\n\n",
+		"event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n",
+		"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"}}\n\n",
+		"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\" Not read.\"}}\n\n",
+	))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	var text strings.Builder
+	if err := provider.Stream(t.Context(), testRequest, collect(&text)); err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "It's DNS." {
+		t.Errorf("text = %q", text.String())
+	}
+
+	req := received()
+	if req.Path != "/v1/messages" || req.Body["stream"] != true {
+		t.Errorf("request = %s %v, want a streaming message", req.Path, req.Body)
+	}
+	assertAnthropicHeaders(t, req.Header)
+}
+
+func TestAnthropicProviderStreamError(t *testing.T) {
+	server, _ := newTestServer(t, respondStream("text/event-stream",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"It's \"}}\n\n",
+		"event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n",
+	))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	var text strings.Builder
+	err := provider.Stream(t.Context(), testRequest, collect(&text))
+	assertError(t, err, "Anthropic API error: Overloaded")
+	if text.String() != "It's " {
+		t.Errorf("text before the error = %q", text.String())
+	}
+}
+
+func TestAnthropicProviderStreamEndedEarly(t *testing.T) {
+	server, _ := newTestServer(t, respondStream("text/event-stream",
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"It's \"}}\n\n",
+		"event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n",
+	))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	var text strings.Builder
+	err := provider.Stream(t.Context(), testRequest, collect(&text))
+	assertError(t, err, "stream ended before the answer was done")
+	if text.String() != "It's " {
+		t.Errorf("text before the end = %q", text.String())
+	}
+}
+
+func TestAnthropicProviderListModels(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"data":[{"id":"claude-a","type":"model"},{"id":"claude-b","type":"model"}],"has_more":false}`))
+	provider := NewAnthropicProvider(testConfig("anthropic", server.URL), server.Client())
+
+	models, err := provider.ListModels(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(models, []string{"claude-a", "claude-b"}) {
+		t.Errorf("models = %v", models)
+	}
+
+	req := received()
+	if req.Method != http.MethodGet || req.Path != "/v1/models?limit=1000" {
+		t.Errorf("request = %s %s, want GET /v1/models?limit=1000", req.Method, req.Path)
+	}
+	assertAnthropicHeaders(t, req.Header)
+}
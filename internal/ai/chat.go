@@ -0,0 +1,83 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const chatSystemPrompt = `You are a Kubernetes expert assistant. The user is looking at the resource below in their cluster and asks questions about it.
+
+Answer concisely and specifically for this resource. Suggest kubectl commands or manifest changes where they help, and say so when the YAML doesn't tell.
+
+` + "```yaml\n%s\n```"
+
+// A conversation about one resource. Messages alternate between the user's
+// questions and the answers, the resource YAML goes along with every request.
+type Chat struct {
+	Resource     string // e.g. "Pod default/web-0"
+	ResourceYAML string
+	Messages     []ChatMessage
+}
+
+func NewResourceChat(resource, resourceYAML string) *Chat {
+	return &Chat{Resource: resource, ResourceYAML: resourceYAML}
+}
+
+// Add a question and its answer to the conversation
+func (c *Chat) Record(question, answer string) {
+	c.Messages = append(c.Messages,
+		ChatMessage{Role: "user", Content: question},
+		ChatMessage{Role: "assistant", Content: answer},
+	)
+}
+
+// Forget the messages, keeping the resource
+func (c *Chat) Clear() {
+	c.Messages = nil
+}
+
+// Transcript with the resource YAML, for saving to a file
+func (c *Chat) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# AI chat: %s\n\n", c.Resource)
+	fmt.Fprintf(&b, "Exported by kubeguide on %s\n\n", time.Now().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "## Resource\n\n```yaml\n%s\n```\n", strings.TrimRight(c.ResourceYAML, "\n"))
+	for _, message := range c.Messages {
+		heading := "You"
+		if message.Role == "assistant" {
+			heading = "Assistant"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", heading, strings.TrimSpace(message.Content))
+	}
+	return b.String()
+}
+
+// The redacted request asking question after the messages so far, as it
+// will be sent
+func (c *Client) PrepareQuestion(chat *Chat, question string) Request {
+	resourceYAML, redacted := c.redactor.RedactManifest(chat.ResourceYAML)
+
+	var messages []ChatMessage
+	for _, message := range append(slices.Clone(chat.Messages), ChatMessage{Role: "user", Content: question}) {
+		content, n := c.redactor.RedactText(message.Content)
+		messages = append(messages, ChatMessage{Role: message.Role, Content: content})
+		redacted += n
+	}
+	return Request{
+		System:      fmt.Sprintf(chatSystemPrompt, resourceYAML),
+		Messages:    messages,
+		Temperature: 0.2,
+		MaxTokens:   2000,
+		Redacted:    redacted,
+	}
+}
+
+// Ask a follow-up question in chat, streaming the answer to onText. The
+// caller records the question and answer, so a failed request leaves the
+// conversation as it was.
+func (c *Client) Ask(ctx context.Context, chat *Chat, question string, onText StreamFunc) (string, error) {
+	return c.Send(ctx, c.PrepareQuestion(chat, question), onText)
+}
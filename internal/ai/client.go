@@ -1,20 +1,20 @@
 package ai
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
+	"strings"
 
 	"kubeguide/internal/config"
+	"kubeguide/internal/kubernetes"
 )
 
+// Runs kubeguide's analyses on the configured provider. Everything sent
+// goes through the redactor first.
 type Client struct {
-	config     *config.AIConfig
-	httpClient *http.Client
+	config   *config.AIConfig
+	provider Provider
+	redactor *Redactor
 }
 
 type ChatMessage struct {
@@ -22,246 +22,147 @@ type ChatMessage struct {
 	Content string `json:"content"`
 }
 
-type ChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	Temperature float64       `json:"temperature,omitempty"`
-	MaxTokens   int           `json:"max_tokens,omitempty"`
+func NewClient(cfg *config.AIConfig) (*Client, error) {
+	redactor, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		config:   cfg,
+		provider: NewProvider(cfg, newHTTPClient()),
+		redactor: redactor,
+	}, nil
 }
 
-type ChatResponse struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-		Code    string `json:"code"`
-	} `json:"error,omitempty"`
+func (c *Client) Provider() Provider {
+	return c.provider
 }
 
-func NewClient(cfg *config.AIConfig) *Client {
-	return &Client{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
+// Whether requests need to be reviewed in a preview before they are sent
+func (c *Client) ConfirmUpload() bool {
+	return c.config.Redaction.ConfirmUpload
 }
 
-func (c *Client) AnalyzePod(ctx context.Context, podYAML string) (string, error) {
-	if c.config.APIKey == "" {
-		return "", fmt.Errorf("AI API key is not configured. Please set KUBEGUIDE_AI_API_KEY environment variable or configure it in ~/.config/kubeguide/config.yaml")
-	}
-
-	systemPrompt := `You are a Kubernetes expert assistant. Analyze the provided pod YAML and identify issues that might be causing failures.
-
-Focus on:
-1. Resource constraints (CPU/memory limits and requests)
-2. Image pull issues 
-3. Configuration problems (environment variables, secrets, configmaps)
-4. Health check configurations
-5. Security context issues
-6. Volume mount problems
-7. Common misconfigurations
-
-Provide a concise analysis with:
-- Root cause identification
-- Specific recommendations to fix issues
-- Best practices suggestions
-
-Keep the response focused and actionable.`
-
-	userPrompt := fmt.Sprintf("Please analyze this Kubernetes pod YAML and help identify why it might be failing:\n\n```yaml\n%s\n```", podYAML)
-
-	if c.config.Provider == "anthropic" {
-		return c.sendAnthropicRequest(ctx, systemPrompt, userPrompt)
-	}
-
-	messages := []ChatMessage{
-		{
-			Role:    "system",
-			Content: systemPrompt,
-		},
-		{
-			Role:    "user",
-			Content: userPrompt,
-		},
+func (c *Client) checkAPIKey() error {
+	if c.config.APIKey == "" && c.provider.Capabilities().RequiresAPIKey {
+		return fmt.Errorf("AI API key is not configured. Please set KUBEGUIDE_AI_API_KEY environment variable or configure it in ~/.config/kubeguide/config.yaml")
 	}
+	return nil
+}
 
-	req := ChatRequest{
-		Model:       c.config.Model,
-		Messages:    messages,
-		Temperature: 0.1, // Low temperature for focused, consistent responses
-		MaxTokens:   1000,
+// Analyze a pod from its YAML and, if not nil, the diagnostic context
+// collected for it, trimmed to the configured token budget
+func (c *Client) AnalyzePod(ctx context.Context, podYAML string, bundle *ContextBundle) (string, error) {
+	if err := c.checkAPIKey(); err != nil {
+		return "", err
 	}
+	return c.provider.Chat(ctx, c.PreparePodAnalysis(podYAML, bundle))
+}
 
-	return c.sendRequest(ctx, req)
+// Like AnalyzePod, but hands the answer to onText as it arrives. Returns the
+// whole answer, or the part received before an error or cancellation of ctx.
+func (c *Client) AnalyzePodStream(ctx context.Context, podYAML string, bundle *ContextBundle, onText StreamFunc) (string, error) {
+	return c.Send(ctx, c.PreparePodAnalysis(podYAML, bundle), onText)
 }
 
-func (c *Client) sendRequest(ctx context.Context, req ChatRequest) (string, error) {
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+// How much diagnostic context to collect for a pod analysis
+func (c *Client) DiagnosticsOptions() kubernetes.DiagnosticsOptions {
+	logLines := c.config.LogLines
+	if logLines == 0 {
+		logLines = DefaultLogLines
 	}
+	return kubernetes.DiagnosticsOptions{LogLines: int64(logLines)}
+}
 
-	endpoint := c.config.BaseURL + "/chat/completions"
-	
-	// Handle Anthropic API which uses a different endpoint
-	if c.config.Provider == "anthropic" {
-		endpoint = c.config.BaseURL + "/v1/messages"
+// Send a prepared request, streaming the answer to onText. Returns the whole
+// answer, or the part received before an error or cancellation of ctx.
+func (c *Client) Send(ctx context.Context, req Request, onText StreamFunc) (string, error) {
+	if err := c.checkAPIKey(); err != nil {
+		return "", err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	var answer strings.Builder
+	collect := func(text string) {
+		answer.WriteString(text)
+		onText(text)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	
-	// Set appropriate headers based on provider
-	if c.config.Provider == "anthropic" {
-		httpReq.Header.Set("x-api-key", c.config.APIKey)
-		httpReq.Header.Set("anthropic-version", "2023-06-01")
+	// Providers that can't stream deliver the whole answer at once
+	var err error
+	if c.provider.Capabilities().Streaming {
+		err = c.provider.Stream(ctx, req, collect)
 	} else {
-		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d to %s (provider: %s): %s", resp.StatusCode, endpoint, c.config.Provider, string(body))
+		var text string
+		if text, err = c.provider.Chat(ctx, req); err == nil {
+			collect(text)
+		}
 	}
-
-	var chatResp ChatResponse
-	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if chatResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", chatResp.Error.Message)
+	if ctx.Err() != nil {
+		err = ctx.Err() // Rather than the failed read it caused
 	}
-
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no response choices returned")
-	}
-
-	return chatResp.Choices[0].Message.Content, nil
-}
-
-type AnthropicRequest struct {
-	Model     string `json:"model"`
-	MaxTokens int    `json:"max_tokens"`
-	Messages  []struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	} `json:"messages"`
-	System string `json:"system,omitempty"`
-}
-
-type AnthropicResponse struct {
-	ID      string `json:"id"`
-	Type    string `json:"type"`
-	Role    string `json:"role"`
-	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
-	Model        string `json:"model"`
-	StopReason   string `json:"stop_reason"`
-	StopSequence string `json:"stop_sequence"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
-	Error *struct {
-		Type    string `json:"type"`
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	return answer.String(), err
 }
 
-func (c *Client) sendAnthropicRequest(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-	req := AnthropicRequest{
-		Model:     c.config.Model,
-		MaxTokens: 1000,
-		System:    systemPrompt,
-		Messages: []struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		}{
-			{
-				Role:    "user",
-				Content: userPrompt,
-			},
-		},
-	}
-
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+// The redacted request analyzing a pod, as it will be sent
+func (c *Client) PreparePodAnalysis(podYAML string, bundle *ContextBundle) Request {
+	podYAML, redacted := c.redactor.RedactManifest(podYAML)
+	systemPrompt, userPrompt := podAnalysisPrompts(podYAML)
+	if bundle != nil && len(bundle.Sections) > 0 {
+		budget := c.config.ContextTokens
+		if budget == 0 {
+			budget = DefaultContextTokens
+		}
+		context, n := c.redactor.RedactText(bundle.Trim(budget).String())
+		userPrompt += "\n\nDiagnostic context collected from the cluster:\n\n" + context
+		redacted += n
+	}
+	return Request{
+		System:      systemPrompt,
+		Messages:    []ChatMessage{{Role: "user", Content: userPrompt}},
+		Temperature: 0.1, // Low temperature for focused, consistent responses
+		MaxTokens:   1000,
+		Redacted:    redacted,
 	}
+}
 
-	endpoint := c.config.BaseURL + "/v1/messages"
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+// Everything a request sends to the provider, for review before sending
+func (c *Client) Preview(req Request) string {
+	model := req.Model
+	if model == "" {
+		model = c.config.Model
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", c.config.APIKey)
-	httpReq.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Provider: %s (%s)\nModel: %s\nValues redacted: %d\n", c.provider.Name(), c.config.BaseURL, model, req.Redacted)
+	if req.System != "" {
+		fmt.Fprintf(&b, "\n=== system ===\n\n%s\n", req.System)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+	for _, message := range req.Messages {
+		fmt.Fprintf(&b, "\n=== %s ===\n\n%s\n", message.Role, message.Content)
 	}
+	return b.String()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Anthropic API request failed with status %d to %s: %s", resp.StatusCode, endpoint, string(body))
-	}
+func podAnalysisPrompts(podYAML string) (systemPrompt, userPrompt string) {
+	systemPrompt = `You are a Kubernetes expert assistant. Analyze the provided pod YAML and identify issues that might be causing failures.
+Events, container logs and the state of related objects may follow the YAML, they usually show the actual error.
 
-	var anthResp AnthropicResponse
-	if err := json.Unmarshal(body, &anthResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
-	}
+Focus on:
+1. Resource constraints (CPU/memory limits and requests)
+2. Image pull issues 
+3. Configuration problems (environment variables, secrets, configmaps)
+4. Health check configurations
+5. Security context issues
+6. Volume mount problems
+7. Common misconfigurations
 
-	if anthResp.Error != nil {
-		return "", fmt.Errorf("Anthropic API error: %s", anthResp.Error.Message)
-	}
+Provide a concise analysis with:
+- Root cause identification
+- Specific recommendations to fix issues
+- Best practices suggestions
 
-	if len(anthResp.Content) == 0 {
-		return "", fmt.Errorf("no content returned from Anthropic API")
-	}
+Keep the response focused and actionable.`
 
-	return anthResp.Content[0].Text, nil
-}
\ No newline at end of file
+	userPrompt = fmt.Sprintf("Please analyze this Kubernetes pod YAML and help identify why it might be failing:\n\n```yaml\n%s\n```", podYAML)
+	return systemPrompt, userPrompt
+}
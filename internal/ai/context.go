@@ -0,0 +1,223 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Defaults for the diagnostic context sent along with a pod analysis
+const (
+	DefaultContextTokens = 6000
+	DefaultLogLines      = 50
+)
+
+// Rough size of text in tokens, about four characters each for English and YAML
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Part of the diagnostic context, e.g. the events or one container's logs
+type ContextSection struct {
+	Title string
+	Body  string
+	// Trimming drops the first lines instead of the last, for logs whose
+	// newest lines are at the end
+	KeepEnd bool
+}
+
+// Diagnostic context of a resource, for the prompt
+type ContextBundle struct {
+	Sections []ContextSection
+}
+
+// Bundle of a pod's events, logs, owners, referenced objects, claims and node
+func PodContext(d *kubernetes.PodDiagnostics) *ContextBundle {
+	b := &ContextBundle{}
+
+	if len(d.Events) > 0 {
+		var lines []string
+		for _, event := range d.Events {
+			line := fmt.Sprintf("%s ago %s %s", kubernetes.Age(event.LastSeen), event.Type, event.Reason)
+			if event.Count > 1 {
+				line += fmt.Sprintf(" (x%d)", event.Count)
+			}
+			if event.Source != "" {
+				line += " from " + event.Source
+			}
+			lines = append(lines, line+": "+event.Message)
+		}
+		b.add("Events, newest first", lines, false)
+	} else {
+		b.add("Events", []string{"No events"}, false)
+	}
+
+	// Previous logs first, they usually show the crash
+	for _, previous := range []bool{true, false} {
+		for _, log := range d.Logs {
+			if log.Previous != previous {
+				continue
+			}
+			title := fmt.Sprintf("Logs of container %s", log.Container)
+			if log.Previous {
+				title = fmt.Sprintf("Logs of container %s before its last restart", log.Container)
+			}
+			text := strings.TrimRight(log.Text, "\n")
+			if text == "" {
+				text = "(empty)"
+			}
+			b.add(title, strings.Split(text, "\n"), true)
+		}
+	}
+
+	if len(d.Owners) > 0 {
+		var lines []string
+		for _, owner := range d.Owners {
+			line := fmt.Sprintf("%s %s", owner.Kind, owner.Name)
+			if len(owner.Status) > 0 {
+				line += " (" + strings.Join(owner.Status, ", ") + ")"
+			}
+			lines = append(lines, line)
+			for _, condition := range owner.Conditions {
+				lines = append(lines, "  condition "+condition)
+			}
+		}
+		b.add("Owners, nearest first", lines, false)
+	}
+
+	if len(d.References) > 0 {
+		var lines []string
+		for _, ref := range d.References {
+			state := "exists"
+			switch {
+			case ref.Error != "":
+				state = "unknown, " + ref.Error
+			case !ref.Exists:
+				state = "MISSING"
+			case len(ref.Missing) > 0:
+				state = "exists, but keys are MISSING: " + strings.Join(ref.Missing, ", ")
+			}
+			if ref.Optional {
+				state += ", optional"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s: %s (used by %s)", ref.Kind, ref.Name, state, strings.Join(ref.UsedBy, "; ")))
+		}
+		b.add("Referenced ConfigMaps and Secrets, existence only", lines, false)
+	}
+
+	if len(d.Claims) > 0 {
+		var lines []string
+		for _, claim := range d.Claims {
+			if !claim.Exists {
+				lines = append(lines, fmt.Sprintf("%s: MISSING", claim.Name))
+				continue
+			}
+			line := fmt.Sprintf("%s: %s", claim.Name, claim.Phase)
+			if claim.StorageClass != "" {
+				line += ", storage class " + claim.StorageClass
+			}
+			if claim.Capacity != "" {
+				line += ", capacity " + claim.Capacity
+			}
+			if len(claim.AccessModes) > 0 {
+				line += ", " + strings.Join(claim.AccessModes, "/")
+			}
+			if claim.VolumeName != "" {
+				line += ", volume " + claim.VolumeName
+			}
+			lines = append(lines, line)
+		}
+		b.add("PersistentVolumeClaims", lines, false)
+	}
+
+	if d.Node != nil {
+		lines := []string{"Name: " + d.Node.Name}
+		if d.Node.Unschedulable {
+			lines = append(lines, "Unschedulable (cordoned)")
+		}
+		for _, condition := range d.Node.Conditions {
+			lines = append(lines, "Condition "+condition)
+		}
+		if len(d.Node.Taints) > 0 {
+			lines = append(lines, "Taints: "+strings.Join(d.Node.Taints, ", "))
+		}
+		b.add("Node", lines, false)
+	} else {
+		b.add("Node", []string{"Not scheduled to a node"}, false)
+	}
+
+	if len(d.Errors) > 0 {
+		b.add("Not collected", d.Errors, false)
+	}
+	return b
+}
+
+func (b *ContextBundle) add(title string, lines []string, keepEnd bool) {
+	b.Sections = append(b.Sections, ContextSection{Title: title, Body: strings.Join(lines, "\n"), KeepEnd: keepEnd})
+}
+
+// Shorten the sections to fit about budget tokens. Sections smaller than an
+// even share stay whole, the rest split what's left, so one long log can't
+// crowd out the events.
+func (b *ContextBundle) Trim(budget int) *ContextBundle {
+	sizes := make([]int, len(b.Sections))
+	order := make([]int, len(b.Sections))
+	for i, section := range b.Sections {
+		sizes[i] = EstimateTokens(section.Title) + EstimateTokens(section.Body)
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int { return sizes[i] - sizes[j] })
+
+	trimmed := &ContextBundle{Sections: slices.Clone(b.Sections)}
+	remaining := budget
+	for n, i := range order {
+		share := remaining / (len(order) - n)
+		if sizes[i] > share {
+			trimmed.Sections[i].Body = trimLines(b.Sections[i].Body, share-EstimateTokens(b.Sections[i].Title), b.Sections[i].KeepEnd)
+			sizes[i] = share
+		}
+		remaining -= sizes[i]
+	}
+	return trimmed
+}
+
+// Drop whole lines from the start or the end until text fits tokens,
+// leaving room for a note on how many were dropped
+func trimLines(text string, tokens int, keepEnd bool) string {
+	lines := strings.Split(text, "\n")
+	if keepEnd {
+		slices.Reverse(lines)
+	}
+	kept, used := 0, 10
+	for _, line := range lines {
+		used += EstimateTokens(line) + 1
+		if used > tokens {
+			break
+		}
+		kept++
+	}
+	if kept == len(lines) {
+		return text
+	}
+
+	note := fmt.Sprintf("... %d lines trimmed", len(lines)-kept)
+	lines = append(lines[:kept], note)
+	if keepEnd {
+		slices.Reverse(lines)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sections as markdown, for the prompt
+func (b *ContextBundle) String() string {
+	var s strings.Builder
+	for i, section := range b.Sections {
+		if i > 0 {
+			s.WriteString("\n\n")
+		}
+		fmt.Fprintf(&s, "### %s\n\n```\n%s\n```", section.Title, section.Body)
+	}
+	return s.String()
+}
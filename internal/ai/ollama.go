@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kubeguide/internal/config"
+)
+
+const OllamaProviderName = "ollama"
+
+func init() {
+	RegisterProvider(OllamaProviderName, NewOllamaProvider)
+}
+
+type ollamaChatRequest struct {
+	Model    string         `json:"model"`
+	Messages []ChatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"` // Ollama streams unless told not to
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+// Response of /api/chat, or one line of its stream
+type ollamaChatResponse struct {
+	Message ChatMessage `json:"message"`
+	Done    bool        `json:"done"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// Provider for Ollama's native API, which needs no API key and streams
+// newline-delimited JSON
+type OllamaProvider struct {
+	config *config.AIConfig
+	api    httpAPI
+}
+
+func NewOllamaProvider(cfg *config.AIConfig, httpClient *http.Client) Provider {
+	// Configs written for Ollama's OpenAI-compatible endpoint end in /v1
+	baseURL := strings.TrimSuffix(strings.TrimSuffix(cfg.BaseURL, "/"), "/v1")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	return &OllamaProvider{
+		config: cfg,
+		api:    newHTTPAPI(OllamaProviderName, baseURL, httpClient, nil),
+	}
+}
+
+func (p *OllamaProvider) Name() string {
+	return OllamaProviderName
+}
+
+func (p *OllamaProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ListModels: true}
+}
+
+func (p *OllamaProvider) chatRequest(req Request, stream bool) ollamaChatRequest {
+	var messages []ChatMessage
+	if req.System != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: req.System})
+	}
+	options := make(map[string]any)
+	if req.Temperature != 0 {
+		options["temperature"] = req.Temperature
+	}
+	if req.MaxTokens != 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	return ollamaChatRequest{
+		Model:    modelOf(req, p.config),
+		Messages: append(messages, req.Messages...),
+		Stream:   stream,
+		Options:  options,
+	}
+}
+
+func (p *OllamaProvider) Chat(ctx context.Context, req Request) (string, error) {
+	var resp ollamaChatResponse
+	if err := p.api.call(ctx, http.MethodPost, "/api/chat", p.chatRequest(req, false), &resp); err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("Ollama error: %s", resp.Error)
+	}
+	return resp.Message.Content, nil
+}
+
+func (p *OllamaProvider) Stream(ctx context.Context, req Request, onText StreamFunc) error {
+	body, err := p.api.do(ctx, http.MethodPost, "/api/chat", p.chatRequest(req, true))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("Ollama error: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			onText(chunk.Message.Content)
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return errStreamEnded
+}
+
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := p.api.call(ctx, http.MethodGet, "/api/tags", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, model := range resp.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOllamaProviderChat(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"model":"test-model","message":{"role":"assistant","content":"It's DNS."},"done":true}`))
+	// Configs written for the OpenAI-compatible endpoint end in /v1
+	provider := NewOllamaProvider(testConfig("ollama", server.URL+"/v1/"), server.Client())
+
+	answer, err := provider.Chat(t.Context(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "It's DNS." {
+		t.Errorf("answer = %q", answer)
+	}
+
+	req := received()
+	if req.Method != http.MethodPost || req.Path != "/api/chat" {
+		t.Errorf("request = %s %s, want POST /api/chat", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none", got)
+	}
+	// Ollama streams unless told not to
+	if req.Body["stream"] != false || req.Body["model"] != "test-model" {
+		t.Errorf("body = %v", req.Body)
+	}
+	options, _ := req.Body["options"].(map[string]any)
+	if options["temperature"] != 0.2 || options["num_predict"] != 100.0 {
+		t.Errorf("options = %v", options)
+	}
+	want := append([]ChatMessage{{Role: "system", Content: "You are a test."}}, testRequest.Messages...)
+	assertMessages(t, bodyMessages(t, req.Body), want)
+}
+
+func TestOllamaProviderCapabilities(t *testing.T) {
+	provider := NewOllamaProvider(testConfig("ollama", ""), http.DefaultClient)
+	if provider.Capabilities().RequiresAPIKey {
+		t.Errorf("Ollama requires an API key")
+	}
+	if got := provider.(*OllamaProvider).api.baseURL; got != "http://localhost:11434" {
+		t.Errorf("default base URL = %q", got)
+	}
+}
+
+func TestOllamaProviderStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		wantText string
+		wantErr  string
+	}{
+		{
+			name: "done",
+			lines: []string{
+				`{"message":{"role":"assistant","content":"It's "},"done":false}` + "\n",
+				"\n",
+				`{"message":{"role":"assistant","content":"DNS."},"done":false}` + "\n",
+				`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop"}` + "\n",
+				`{"message":{"role":"assistant","content":" Not read."},"done":false}` + "\n",
+			},
+			wantText: "It's DNS.",
+		},
+		{
+			name: "ended early",
+			lines: []string{
+				`{"message":{"role":"assistant","content":"It's "},"done":false}` + "\n",
+			},
+			wantText: "It's ",
+			wantErr:  "stream ended before the answer was done",
+		},
+		{
+			name: "error",
+			lines: []string{
+				`{"message":{"role":"assistant","content":"It's "},"done":false}` + "\n",
+				`{"error":"model ran out of memory"}` + "\n",
+			},
+			wantText: "It's ",
+			wantErr:  "Ollama error: model ran out of memory",
+		},
+		{
+			name:    "not JSON",
+			lines:   []string{"data: {}\n"},
+			wantErr: "failed to unmarshal stream chunk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := newTestServer(t, respondStream("application/x-ndjson", tt.lines...))
+			provider := NewOllamaProvider(testConfig("ollama", server.URL), server.Client())
+
+			var text strings.Builder
+			err := provider.Stream(t.Context(), testRequest, collect(&text))
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" {
+				assertError(t, err, tt.wantErr)
+			}
+			if text.String() != tt.wantText {
+				t.Errorf("text = %q, want %q", text.String(), tt.wantText)
+			}
+			if req := received(); req.Path != "/api/chat" || req.Body["stream"] != true {
+				t.Errorf("request = %s %v, want a streaming chat", req.Path, req.Body)
+			}
+		})
+	}
+}
+
+func TestOllamaProviderListModels(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"models":[{"name":"llama3.2:latest"},{"name":"qwen2.5:7b"}]}`))
+	provider := NewOllamaProvider(testConfig("ollama", server.URL), server.Client())
+
+	models, err := provider.ListModels(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(models, []string{"llama3.2:latest", "qwen2.5:7b"}) {
+		t.Errorf("models = %v", models)
+	}
+	if req := received(); req.Method != http.MethodGet || req.Path != "/api/tags" {
+		t.Errorf("request = %s %s, want GET /api/tags", req.Method, req.Path)
+	}
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"kubeguide/internal/config"
+)
+
+const OpenAIProviderName = "openai"
+
+func init() {
+	RegisterProvider(OpenAIProviderName, NewOpenAIProvider)
+}
+
+type ChatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Temperature float64       `json:"temperature,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type ChatResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// Chunk of a chat completion stream
+type chatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Provider for the OpenAI chat completions API and the many servers
+// compatible with it, e.g. vLLM, LiteLLM or Ollama's /v1 endpoint
+type OpenAIProvider struct {
+	config *config.AIConfig
+	api    httpAPI
+}
+
+func NewOpenAIProvider(cfg *config.AIConfig, httpClient *http.Client) Provider {
+	header := http.Header{}
+	if cfg.APIKey != "" {
+		header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	return &OpenAIProvider{
+		config: cfg,
+		api:    newHTTPAPI(cfg.Provider, cfg.BaseURL, httpClient, header),
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return OpenAIProviderName
+}
+
+func (p *OpenAIProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, ListModels: true, RequiresAPIKey: true}
+}
+
+// The system prompt is the first message
+func (p *OpenAIProvider) chatRequest(req Request) ChatRequest {
+	var messages []ChatMessage
+	if req.System != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: req.System})
+	}
+	return ChatRequest{
+		Model:       modelOf(req, p.config),
+		Messages:    append(messages, req.Messages...),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+}
+
+func (p *OpenAIProvider) Chat(ctx context.Context, req Request) (string, error) {
+	var chatResp ChatResponse
+	if err := p.api.call(ctx, http.MethodPost, "/chat/completions", p.chatRequest(req), &chatResp); err != nil {
+		return "", err
+	}
+
+	if chatResp.Error != nil {
+		return "", fmt.Errorf("API error: %s", chatResp.Error.Message)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned")
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+// Server-sent events with a JSON chunk each, ending with [DONE]
+func (p *OpenAIProvider) Stream(ctx context.Context, req Request, onText StreamFunc) error {
+	chatReq := p.chatRequest(req)
+	chatReq.Stream = true
+
+	body, err := p.api.do(ctx, http.MethodPost, "/chat/completions", chatReq)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return readServerSentEvents(body, func(event, data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+		var chunk chatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return false, fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				onText(choice.Delta.Content)
+			}
+		}
+		return false, nil
+	})
+}
+
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := p.api.call(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, model := range resp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIProviderChat(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"choices":[{"message":{"role":"assistant","content":"It's DNS."}}]}`))
+	provider := NewOpenAIProvider(testConfig("openai", server.URL+"/"), server.Client())
+
+	answer, err := provider.Chat(t.Context(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "It's DNS." {
+		t.Errorf("answer = %q", answer)
+	}
+
+	req := received()
+	if req.Method != http.MethodPost || req.Path != "/chat/completions" {
+		t.Errorf("request = %s %s, want POST /chat/completions", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := req.Header.Get("x-api-key"); got != "" {
+		t.Errorf("x-api-key = %q, want none", got)
+	}
+	if req.Body["model"] != "test-model" || req.Body["temperature"] != 0.2 || req.Body["max_tokens"] != 100.0 {
+		t.Errorf("body = %v", req.Body)
+	}
+	if _, ok := req.Body["stream"]; ok {
+		t.Errorf("stream set in body of a chat request")
+	}
+	// The system prompt is the first message
+	want := append([]ChatMessage{{Role: "system", Content: "You are a test."}}, testRequest.Messages...)
+	assertMessages(t, bodyMessages(t, req.Body), want)
+}
+
+func TestOpenAIProviderChatErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"error object", `{"error":{"message":"model not found"}}`, "API error: model not found"},
+		{"no choices", `{"choices":[]}`, "no response choices"},
+		{"not JSON", `<html>`, "failed to unmarshal response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, respondJSON(tt.response))
+			_, err := NewOpenAIProvider(testConfig("openai", server.URL), server.Client()).Chat(t.Context(), testRequest)
+			assertError(t, err, tt.want)
+		})
+	}
+}
+
+func TestOpenAIProviderStream(t *testing.T) {
+	server, received := newTestServer(t, respondStream("text/event-stream",
+		": keep-alive\n\n",
+		"data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n",
+		"data: {\"choices\":[{\"delta\":{\"content\":\"It's \"}}]}\n\n",
+		"data: {\"choices\":[{\"delta\":{\"content\":\"DNS.\"},\"finish_reason\":\"stop\"}]}\n\n",
+		"data: [DONE]\n\n",
+		"data: {\"choices\":[{\"delta\":{\"content\":\" Not read.\"}}]}\n\n",
+	))
+	provider := NewOpenAIProvider(testConfig("openai", server.URL), server.Client())
+
+	var text strings.Builder
+	if err := provider.Stream(t.Context(), testRequest, collect(&text)); err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "It's DNS." {
+		t.Errorf("text = %q", text.String())
+	}
+
+	req := received()
+	if req.Path != "/chat/completions" || req.Body["stream"] != true {
+		t.Errorf("request = %s %v, want a streaming chat completion", req.Path, req.Body)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestOpenAIProviderStreamError(t *testing.T) {
+	server, _ := newTestServer(t, respondStream("text/event-stream",
+		"data: {\"choices\":[{\"delta\":{\"content\":\"It's \"}}]}\n\n",
+		"data: {\"error\":{\"message\":\"overloaded\"}}\n\n",
+	))
+	provider := NewOpenAIProvider(testConfig("openai", server.URL), server.Client())
+
+	var text strings.Builder
+	err := provider.Stream(t.Context(), testRequest, collect(&text))
+	assertError(t, err, "API error: overloaded")
+	if text.String() != "It's " {
+		t.Errorf("text before the error = %q", text.String())
+	}
+}
+
+func TestOpenAIProviderStreamEndedEarly(t *testing.T) {
+	server, _ := newTestServer(t, respondStream("text/event-stream",
+		"data: {\"choices\":[{\"delta\":{\"content\":\"It's \"}}]}\n\n",
+	))
+	provider := NewOpenAIProvider(testConfig("openai", server.URL), server.Client())
+
+	var text strings.Builder
+	err := provider.Stream(t.Context(), testRequest, collect(&text))
+	assertError(t, err, "stream ended before the answer was done")
+	if text.String() != "It's " {
+		t.Errorf("text before the end = %q", text.String())
+	}
+}
+
+func TestOpenAIProviderListModels(t *testing.T) {
+	server, received := newTestServer(t, respondJSON(`{"object":"list","data":[{"id":"gpt-4o"},{"id":"gpt-4o-mini"}]}`))
+	provider := NewOpenAIProvider(testConfig("openai", server.URL), server.Client())
+
+	models, err := provider.ListModels(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(models, []string{"gpt-4o", "gpt-4o-mini"}) {
+		t.Errorf("models = %v", models)
+	}
+
+	req := received()
+	if req.Method != http.MethodGet || req.Path != "/models" {
+		t.Errorf("request = %s %s, want GET /models", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
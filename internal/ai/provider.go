@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"kubeguide/internal/config"
+)
+
+// A model API kubeguide can talk to
+type Provider interface {
+	Name() string
+	Capabilities() Capabilities
+	// The whole answer to req
+	Chat(ctx context.Context, req Request) (string, error)
+	// Hands the answer to onText as the model writes it
+	Stream(ctx context.Context, req Request, onText StreamFunc) error
+	// IDs of the models the API serves
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// What a provider supports besides chatting
+type Capabilities struct {
+	Streaming      bool // Stream delivers text as it is written, otherwise Client uses Chat
+	ListModels     bool
+	RequiresAPIKey bool // False for local servers such as Ollama
+}
+
+// Provider independent chat request
+type Request struct {
+	Model       string // Empty for the configured model
+	System      string
+	Messages    []ChatMessage // Alternating "user" and "assistant" messages
+	Temperature float64
+	MaxTokens   int
+	Redacted    int // Values the redactor masked, not sent
+}
+
+// Creates a provider from the AI config. The HTTP client has no overall
+// timeout, so streams may run as long as the model writes.
+type ProviderFactory func(cfg *config.AIConfig, httpClient *http.Client) Provider
+
+var providers = make(map[string]ProviderFactory)
+
+// Make a provider available under name, the value of ai.provider in the config
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers[name] = factory
+}
+
+// Names of the registered providers, sorted
+func ProviderNames() []string {
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// The provider configured in cfg. Names that aren't registered get the
+// OpenAI-compatible provider, the API most hosted models offer.
+func NewProvider(cfg *config.AIConfig, httpClient *http.Client) Provider {
+	factory, ok := providers[cfg.Provider]
+	if !ok {
+		factory = providers[OpenAIProviderName]
+	}
+	return factory(cfg, httpClient)
+}
+
+// Default HTTP client of providers. Only waiting for the response headers
+// times out, which covers the whole answer unless it is streamed.
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	return &http.Client{Transport: transport}
+}
+
+// Shared plumbing of providers speaking JSON over HTTP
+type httpAPI struct {
+	provider string
+	baseURL  string
+	client   *http.Client
+	header   http.Header // Authentication and version headers sent with every request
+}
+
+func newHTTPAPI(provider, baseURL string, httpClient *http.Client, header http.Header) httpAPI {
+	return httpAPI{
+		provider: provider,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		client:   httpClient,
+		header:   header,
+	}
+}
+
+// Send a request and return the body of a successful response. Other
+// statuses become an error with the start of the body.
+func (api httpAPI) do(ctx context.Context, method, path string, body any) (io.ReadCloser, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	endpoint := api.baseURL + path
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range api.header {
+		httpReq.Header[name] = values
+	}
+
+	resp, err := api.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("API request failed with status %d to %s (provider: %s): %s", resp.StatusCode, endpoint, api.provider, string(data))
+	}
+	return resp.Body, nil
+}
+
+// Send a request and decode the JSON response into out
+func (api httpAPI) call(ctx context.Context, method, path string, body, out any) error {
+	respBody, err := api.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+
+	if err := json.NewDecoder(respBody).Decode(out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// The model of req, or the configured one
+func modelOf(req Request, cfg *config.AIConfig) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return cfg.Model
+}
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kubeguide/internal/config"
+)
+
+// Request received by a test server
+type recordedRequest struct {
+	Method string
+	Path   string // With the query, e.g. "/v1/models?limit=1000"
+	Header http.Header
+	Body   map[string]any
+}
+
+// Start a local stand-in for a provider API. respond writes the response to
+// every request, received returns the requests in order.
+func newTestServer(t *testing.T, respond func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, func() recordedRequest) {
+	t.Helper()
+	requests := make(chan recordedRequest, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := recordedRequest{Method: r.Method, Path: r.URL.RequestURI(), Header: r.Header.Clone()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			if err := json.Unmarshal(data, &recorded.Body); err != nil {
+				t.Errorf("request body is not JSON: %v: %s", err, data)
+			}
+		}
+		requests <- recorded
+		respond(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	received := func() recordedRequest {
+		t.Helper()
+		select {
+		case r := <-requests:
+			return r
+		default:
+			t.Fatal("no request received")
+			return recordedRequest{}
+		}
+	}
+	return server, received
+}
+
+// Respond with a JSON document
+func respondJSON(body string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}
+}
+
+// Respond with a stream, flushing after every part
+func respondStream(contentType string, parts ...string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		for _, part := range parts {
+			io.WriteString(w, part)
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func testConfig(provider, baseURL string) *config.AIConfig {
+	return &config.AIConfig{Provider: provider, BaseURL: baseURL, Model: "test-model", APIKey: "test-key"}
+}
+
+var testRequest = Request{
+	System: "You are a test.",
+	Messages: []ChatMessage{
+		{Role: "user", Content: "Why?"},
+		{Role: "assistant", Content: "Because."},
+		{Role: "user", Content: "Why not?"},
+	},
+	Temperature: 0.2,
+	MaxTokens:   100,
+}
+
+// Collect streamed text
+func collect(text *strings.Builder) StreamFunc {
+	return func(s string) { text.WriteString(s) }
+}
+
+// Roles and contents of the messages in a recorded body
+func bodyMessages(t *testing.T, body map[string]any) []ChatMessage {
+	t.Helper()
+	items, ok := body["messages"].([]any)
+	if !ok {
+		t.Fatalf("messages missing from body: %v", body)
+	}
+	var messages []ChatMessage
+	for _, item := range items {
+		m := item.(map[string]any)
+		messages = append(messages, ChatMessage{Role: m["role"].(string), Content: m["content"].(string)})
+	}
+	return messages
+}
+
+func assertMessages(t *testing.T, got, want []ChatMessage) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func assertError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want one containing %q", err, want)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     string
+	}{
+		{"openai", OpenAIProviderName},
+		{"anthropic", AnthropicProviderName},
+		{"ollama", OllamaProviderName},
+		{"groq", OpenAIProviderName},
+		{"", OpenAIProviderName},
+	}
+	for _, tt := range tests {
+		got := NewProvider(testConfig(tt.provider, "http://localhost"), http.DefaultClient).Name()
+		if got != tt.want {
+			t.Errorf("NewProvider(%q) = %s, want %s", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPErrorStatus(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid key"}}`, http.StatusUnauthorized)
+	})
+	for _, name := range ProviderNames() {
+		provider := NewProvider(testConfig(name, server.URL), server.Client())
+		_, err := provider.Chat(t.Context(), testRequest)
+		assertError(t, err, "status 401")
+		assertError(t, err, "invalid key")
+	}
+}
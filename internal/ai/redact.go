@@ -0,0 +1,233 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"sigs.k8s.io/yaml"
+
+	"kubeguide/internal/config"
+)
+
+// Replaces masked values
+const redactedValue = "<redacted>"
+
+// Parts of env var, annotation and ConfigMap key names whose values are
+// masked, case-insensitive. RedactionConfig.SensitiveKeys adds to these.
+var defaultSensitiveKeys = []string{
+	`passw`, `pwd`, `secret`, `token`, `credential`,
+	`api[-_.]?key`, `access[-_.]?key`, `private[-_.]?key`,
+}
+
+var (
+	privateKeyPattern     = regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+	authHeaderPattern     = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`)
+	urlCredentialsPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+):[^/\s@]+@`)
+	// Candidates for random tokens, checked further by looksRandom
+	tokenCandidatePattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`)
+)
+
+// Masks secrets in what is sent to a provider: Secret data, values of
+// sensitive env vars and annotations, bearer tokens, private keys, passwords
+// in URLs and random-looking strings such as API keys
+type Redactor struct {
+	sensitiveKey   *regexp.Regexp // Matches sensitive key names
+	sensitivePairs *regexp.Regexp // Matches "key: value" and "key=value" with a sensitive key in text
+}
+
+func NewRedactor(cfg config.RedactionConfig) (*Redactor, error) {
+	keys := append(append([]string{}, defaultSensitiveKeys...), cfg.SensitiveKeys...)
+	for _, key := range cfg.SensitiveKeys {
+		if _, err := regexp.Compile(key); err != nil {
+			return nil, fmt.Errorf("sensitive key pattern %q: %w", key, err)
+		}
+	}
+	var textKeys []string
+	for _, key := range keys {
+		textKeys = append(textKeys, textKeyPattern(key))
+	}
+
+	return &Redactor{
+		sensitiveKey: regexp.MustCompile(`(?i)(?:` + strings.Join(keys, "|") + `)`),
+		// The key starts after a character that can't be part of it, which
+		// is kept in the first group along with the key
+		sensitivePairs: regexp.MustCompile(`(?im)((?:^|[^\w.-])(?:` + strings.Join(textKeys, "|") + `)["']?\s*[:=]\s*["']?)([^\s"',;&]+)`),
+	}, nil
+}
+
+// Pattern for a key name in text, where the name is only delimited by the
+// characters around it. Anchors keep their meaning there: "^db_url$" matches
+// DB_URL=..., but not OLD_DB_URL=... or DB_URL_OLD=...
+func textKeyPattern(key string) string {
+	start, end := `[\w.-]*`, `[\w.-]*`
+	if strings.HasPrefix(key, "^") {
+		key, start = key[1:], ""
+	}
+	if strings.HasSuffix(key, "$") && !strings.HasSuffix(key, `\$`) {
+		key, end = key[:len(key)-1], ""
+	}
+	return start + "(?:" + key + ")" + end
+}
+
+// Mask secrets in a YAML manifest, keeping its structure. Returns the
+// masked manifest and how many values were masked. Text that doesn't parse
+// as a manifest is masked like any other text.
+func (r *Redactor) RedactManifest(manifest string) (string, int) {
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil || obj == nil {
+		return r.RedactText(manifest)
+	}
+
+	p := &redaction{redactor: r}
+	p.object(obj)
+	if p.count == 0 {
+		return manifest, 0 // Keep the original formatting
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return r.RedactText(manifest)
+	}
+	return string(data), p.count
+}
+
+// Mask secrets in free text such as logs and event messages
+func (r *Redactor) RedactText(text string) (string, int) {
+	p := &redaction{redactor: r}
+	return p.text(text), p.count
+}
+
+// One pass over a manifest or text, counting what it masks
+type redaction struct {
+	redactor *Redactor
+	count    int
+}
+
+func (p *redaction) mask() string {
+	p.count++
+	return redactedValue
+}
+
+func (p *redaction) object(obj map[string]any) {
+	// Every value of a Secret is sensitive, whatever its key
+	if obj["kind"] == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := obj[field].(map[string]any); ok {
+				for key := range data {
+					data[key] = p.mask()
+				}
+			}
+		}
+	}
+	p.walk(obj, "")
+}
+
+// Mask values below v, which is found under key
+func (p *redaction) walk(v any, key string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		// Items of env lists: {name, value} and {name, valueFrom}
+		if name, ok := v["name"].(string); ok && key == "env" {
+			if value, ok := v["value"].(string); ok && value != "" && p.redactor.sensitiveKey.MatchString(name) {
+				v["value"] = p.mask()
+			}
+		}
+		for field, item := range v {
+			// Annotations and ConfigMap data are free-form key-value pairs
+			if (key == "annotations" || key == "data") && p.redactor.sensitiveKey.MatchString(field) {
+				if value, ok := item.(string); ok && value != "" && value != redactedValue {
+					v[field] = p.mask()
+					continue
+				}
+			}
+			v[field] = p.walk(item, field)
+		}
+	case []any:
+		for i := range v {
+			v[i] = p.walk(v[i], key)
+		}
+	case string:
+		return p.string(v)
+	}
+	return v
+}
+
+// Strings holding JSON, such as the last-applied-configuration annotation,
+// are masked like the manifest itself
+func (p *redaction) string(s string) string {
+	if s == redactedValue {
+		return s
+	}
+	if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "{") {
+		var obj map[string]any
+		if json.Unmarshal([]byte(trimmed), &obj) == nil {
+			before := p.count
+			p.object(obj)
+			if p.count == before {
+				return s
+			}
+			var b strings.Builder
+			encoder := json.NewEncoder(&b)
+			encoder.SetEscapeHTML(false) // Keep "<redacted>" readable
+			if encoder.Encode(obj) == nil {
+				return strings.TrimSuffix(b.String(), "\n")
+			}
+		}
+	}
+	return p.text(s)
+}
+
+func (p *redaction) text(s string) string {
+	s = privateKeyPattern.ReplaceAllStringFunc(s, func(string) string {
+		p.count++
+		return "<redacted private key>"
+	})
+	s = authHeaderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		scheme, _, _ := strings.Cut(match, " ")
+		return scheme + " " + p.mask()
+	})
+	s = urlCredentialsPattern.ReplaceAllStringFunc(s, func(match string) string {
+		return urlCredentialsPattern.ReplaceAllString(match, "${1}:") + p.mask() + "@"
+	})
+	s = p.redactor.sensitivePairs.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasSuffix(match, redactedValue) {
+			return match
+		}
+		groups := p.redactor.sensitivePairs.FindStringSubmatch(match)
+		return groups[1] + p.mask()
+	})
+	s = tokenCandidatePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if !looksRandom(match) {
+			return match
+		}
+		return p.mask()
+	})
+	return s
+}
+
+// Whether s looks like a generated secret: mixed case letters and digits
+// with high entropy. Kubernetes names are lowercase and hashes are hex, so
+// neither matches.
+func looksRandom(s string) bool {
+	var upper, lower, digit bool
+	counts := make(map[rune]int)
+	for _, c := range s {
+		upper = upper || unicode.IsUpper(c)
+		lower = lower || unicode.IsLower(c)
+		digit = digit || unicode.IsDigit(c)
+		counts[c]++
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		f := float64(n) / float64(len(s))
+		entropy -= f * math.Log2(f)
+	}
+	return entropy >= 4.0
+}
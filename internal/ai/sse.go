@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Called with each piece of text as the model writes it
+type StreamFunc func(text string)
+
+// A stream closed without its final message, e.g. a dropped connection
+var errStreamEnded = errors.New("stream ended before the answer was done")
+
+// Read a text/event-stream, calling onEvent with the name and data of every
+// event until it returns done or an error. Fails with errStreamEnded if the
+// stream ends before onEvent is done.
+func readServerSentEvents(r io.Reader, onEvent func(event, data string) (done bool, err error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line ends the event
+			if len(data) > 0 {
+				done, err := onEvent(event, strings.Join(data, "\n"))
+				if done || err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment, sent to keep the connection open
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	// The stream may end without a blank line after the last event
+	if len(data) > 0 {
+		done, err := onEvent(event, strings.Join(data, "\n"))
+		if done || err != nil {
+			return err
+		}
+	}
+	return errStreamEnded
+}
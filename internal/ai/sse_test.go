@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadServerSentEvents(t *testing.T) {
+	type event struct{ Name, Data string }
+	tests := []struct {
+		name   string
+		stream string
+		want   []event
+	}{
+		{
+			name:   "data only",
+			stream: "data: one\n\ndata: two\n\n",
+			want:   []event{{"", "one"}, {"", "two"}},
+		},
+		{
+			name:   "named events",
+			stream: "event: ping\ndata: {}\n\nevent: delta\ndata: {\"text\":\"hi\"}\n\n",
+			want:   []event{{"ping", "{}"}, {"delta", `{"text":"hi"}`}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\n\n",
+			want:   []event{{"", "first\nsecond"}},
+		},
+		{
+			name:   "comments and empty events",
+			stream: ": keep-alive\n\nevent: nothing\n\ndata: one\n\n",
+			want:   []event{{"", "one"}},
+		},
+		{
+			name:   "no space after colon",
+			stream: "event:delta\ndata:one\n\n",
+			want:   []event{{"delta", "one"}},
+		},
+		{
+			name:   "CRLF line endings",
+			stream: "event: delta\r\ndata: one\r\n\r\n",
+			want:   []event{{"delta", "one"}},
+		},
+		{
+			name:   "no blank line after the last event",
+			stream: "data: one\n\ndata: two",
+			want:   []event{{"", "one"}, {"", "two"}},
+		},
+		{
+			name:   "event name doesn't carry over",
+			stream: "event: delta\ndata: one\n\ndata: two\n\n",
+			want:   []event{{"delta", "one"}, {"", "two"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []event
+			err := readServerSentEvents(strings.NewReader(tt.stream), func(name, data string) (bool, error) {
+				got = append(got, event{name, data})
+				return false, nil
+			})
+			if !errors.Is(err, errStreamEnded) {
+				t.Errorf("error = %v, want the stream to end early", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("event %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadServerSentEventsStops(t *testing.T) {
+	stream := "data: one\n\ndata: [DONE]\n\ndata: two\n\n"
+
+	var got []string
+	err := readServerSentEvents(strings.NewReader(stream), func(name, data string) (bool, error) {
+		got = append(got, data)
+		return data == "[DONE]", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "one,[DONE]" {
+		t.Errorf("events = %q, want none after done", got)
+	}
+
+	// Done with the last event, without a blank line after it
+	err = readServerSentEvents(strings.NewReader("data: one\n\ndata: [DONE]"), func(name, data string) (bool, error) {
+		return data == "[DONE]", nil
+	})
+	if err != nil {
+		t.Errorf("error = %v, want done", err)
+	}
+
+	failed := errors.New("failed")
+	got = nil
+	err = readServerSentEvents(strings.NewReader(stream), func(name, data string) (bool, error) {
+		got = append(got, data)
+		return false, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("error = %v, want the callback's", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("events = %q, want none after the error", got)
+	}
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/ai"
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+)
+
+func (a *App) performAIAnalysis() {
+	if a.aiClient == nil {
+		a.showErrorModal("AI not configured", "AI analysis is not available. Please configure AI settings in ~/.config/kubeguide/config.yaml or set environment variables.")
+		return
+	}
+
+	// Get currently selected resource
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if row == nil {
+		a.showErrorModal("No selection", "Please select a resource to analyze.")
+		return
+	}
+	selected := *row
+
+	// Only analyze pods for now
+	if selected.Kind != "Pod" {
+		a.showErrorModal("Unsupported resource", "AI analysis is currently only supported for pods.")
+		return
+	}
+
+	// Check if pod is in a failed state
+	rowText := strings.ToLower(strings.Join(selected.Cells, " "))
+	if !strings.Contains(rowText, "failed") &&
+		!strings.Contains(rowText, "error") &&
+		!strings.Contains(rowText, "crashloopbackoff") &&
+		!strings.Contains(rowText, "imagepullbackoff") {
+		a.showInfoModal("Pod status", "AI analysis is most useful for failed or problematic pods. This pod appears to be running normally.")
+		return
+	}
+
+	a.showAIAnalysisResults(selected)
+}
+
+func (a *App) performAIAnalysisForce() {
+	// This is a simplified version that skips the health check
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if row == nil || row.Kind != "Pod" {
+		return
+	}
+	a.showAIAnalysisResults(*row)
+}
+
+// Open the analysis view and stream the answer into it as the model writes
+// it. 'x' stops the request, Esc stops it as well and closes the view.
+func (a *App) showAIAnalysisResults(selected kubernetes.ResourceRow) {
+	// Closing the view cancels viewCtx, stopping only cancels the request
+	viewCtx, closeView := context.WithCancel(context.Background())
+	ctx, stop := context.WithCancel(viewCtx)
+	a.aiCancel = closeView
+
+	textView := tview.NewTextView().
+		SetTextAlign(tview.AlignLeft).
+		SetWrap(true).
+		SetScrollable(true).
+		SetChangedFunc(func() { a.app.Draw() })
+
+	textView.SetBackgroundColor(tcell.ColorBlack)
+	textView.SetTextColor(tcell.ColorWhite)
+	textView.SetBorder(true)
+
+	setStatus := func(status string) {
+		textView.SetTitle(fmt.Sprintf(" AI Analysis: %s - %s ", selected.Name, status))
+	}
+	setStatus("Collecting events and logs... (Press 'x' to stop, Esc to close)")
+
+	var sent *ai.Request
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Rune() == 'x':
+			stop()
+			return nil
+		case event.Rune() == 'p' && sent != nil:
+			a.showAIPreview(*sent, nil, nil)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("ai-analysis", textView, true, true)
+	a.currentMode = modes.AIAnalysis
+
+	// The view may have been closed by the time results arrive
+	isOpen := func() bool {
+		return viewCtx.Err() == nil
+	}
+
+	send := func(req ai.Request) {
+		sent = &req
+		setStatus("Analyzing... (Press 'x' to stop, 'p' to show what was sent, Esc to close)")
+
+		go func() {
+			defer stop()
+
+			// TextView is safe to write to from other goroutines and redraws
+			// through the changed func
+			analysis, err := a.aiClient.Send(ctx, req, func(text string) {
+				fmt.Fprint(textView, text)
+			})
+
+			a.app.QueueUpdateDraw(func() {
+				if !isOpen() {
+					return
+				}
+				switch {
+				case err == nil:
+					setStatus("Press 'esc' to close")
+				case errors.Is(err, context.Canceled):
+					setStatus("Stopped (Press 'esc' to close)")
+				case analysis == "":
+					a.closeAIAnalysis()
+					a.showErrorModal("AI analysis failed", fmt.Sprintf("Error: %v", err))
+				default:
+					fmt.Fprintf(textView, "\n\nThe answer is incomplete: %v", err)
+					setStatus("Failed (Press 'esc' to close)")
+				}
+			})
+		}()
+	}
+
+	go func() {
+		yamlContent, err := a.getResourceDetails(selected.GVR, selected.Namespace, selected.Name)
+		var diagnostics *kubernetes.PodDiagnostics
+		if err == nil {
+			diagnostics, err = a.kubeClient.CollectPodDiagnostics(ctx, selected.Namespace, selected.Name, a.aiClient.DiagnosticsOptions())
+		}
+		a.app.QueueUpdateDraw(func() {
+			if !isOpen() {
+				stop()
+				return
+			}
+			if err != nil {
+				a.closeAIAnalysis()
+				a.showErrorModal("Failed to get pod details", fmt.Sprintf("Error: %v", err))
+				return
+			}
+
+			req := a.aiClient.PreparePodAnalysis(yamlContent, ai.PodContext(diagnostics))
+			if !a.aiClient.ConfirmUpload() {
+				send(req)
+				return
+			}
+			setStatus("Waiting for confirmation (Press Esc to close)")
+			a.showAIPreview(req, func() { send(req) }, func() {
+				stop()
+				setStatus("Not sent (Press 'esc' to close)")
+			})
+		})
+	}()
+}
+
+// Show everything req sends to the provider, after redaction. With onSend,
+// Enter sends it and Esc calls onCancel, otherwise both just close the
+// preview.
+func (a *App) showAIPreview(req ai.Request, onSend, onCancel func()) {
+	textView := tview.NewTextView().
+		SetTextAlign(tview.AlignLeft).
+		SetWrap(true).
+		SetScrollable(true).
+		SetText(a.aiClient.Preview(req))
+
+	textView.SetBackgroundColor(tcell.ColorBlack)
+	textView.SetTextColor(tcell.ColorWhite)
+	textView.SetBorder(true)
+	if onSend != nil {
+		textView.SetTitle(" Send to AI provider? (Enter to send, Esc to cancel) ")
+	} else {
+		textView.SetTitle(" Sent to AI provider (Press 'esc' to close) ")
+	}
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			a.pages.RemovePage("ai-preview")
+			if onSend != nil {
+				onSend()
+			}
+			return nil
+		case tcell.KeyEsc:
+			a.pages.RemovePage("ai-preview")
+			if onCancel != nil {
+				onCancel()
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("ai-preview", textView, true, true)
+}
+
+func (a *App) closeAIAnalysis() {
+	if a.aiCancel != nil {
+		a.aiCancel()
+		a.aiCancel = nil
+	}
+	a.pages.RemovePage("ai-analysis")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
@@ -2,48 +2,87 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"slices"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
 
 	"kubeguide/internal/ai"
 	"kubeguide/internal/config"
 	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/lint"
 	"kubeguide/internal/modes"
 	"kubeguide/internal/navigation"
 	"kubeguide/internal/ui"
+	"kubeguide/internal/validation"
 )
 
-type Resource struct {
-	Type   string
-	Name   string
-	Status string
+// Resource types shown together when the "all" filter is selected
+var allResourceTypes = []string{"pods", "services", "deployments", "configmaps", "secrets"}
+
+// Startup options, typically set from command line flags
+type Options struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+	Schemas    validation.OfflineOptions // Schemas to validate against besides the cluster's
 }
 
 type App struct {
 	app                 *tview.Application
+	options             Options
 	kubeClient          *kubernetes.UnifiedClient
 	aiClient            *ai.Client
 	config              *config.Config
+	configErr           error // Config problem that stops startup
 	explorer            *ui.Explorer
 	welcome             *ui.Welcome
 	resourceDetails     *ui.ResourceDetails
 	currentMode         modes.Mode
+	currentContext      string
 	currentNamespace    string
 	currentResourceType string
 	pages               *tview.Pages
 	namespaces          []string
-	explorerList        *tview.List
+	explorerTable       *tview.Table
+	explorerLoads       []*resourceLoad // Resource types currently shown in explorerTable
+	explorerGeneration  int             // Incremented on every reload to drop stale watch events
+	explorerWatches     []func()
+	informers           *kubernetes.InformerManager
+	logView             *ui.LogView
+	portForwards        []*kubernetes.PortForward
+	portForwardPanel    *ui.PortForwardPanel
+	applyView           *ui.ApplyView
+	applyPlan           []*kubernetes.ApplyPlanItem
+	applyForce          bool
+	applyCancel         context.CancelFunc
+	applyOnClose        func()
+	editor              *ui.Editor
+	linter              *lint.Linter
+	offlineSchemas      []validation.SchemaSource // From Options.Schemas, consulted before the cluster
+	validator           *validation.Validator     // Nil without any schemas
+	deprecationTarget   string                    // Kubernetes version the deprecation report checks against
+	deprecationFiles    []string                  // Manifest files and directories included in the deprecation report
+	deprecationCancel   context.CancelFunc        // Stops the running scan
+	deprecationScan     int                       // Incremented on every scan to drop results of older ones
+	aiCancel            context.CancelFunc        // Stops the streaming AI analysis
+	aiChats             map[string]*ai.Chat       // Conversations by resource, kept for the session
+	aiChat              *ai.Chat                  // Shown in aiChatView
+	aiChatView          *ui.ChatView
+	aiChatCancel        context.CancelFunc // Stops the answer in progress, nil while idle
 	keyBindings         *navigation.KeyBindings
 }
 
-func New() *App {
+func New(opts Options) *App {
 	app := tview.NewApplication()
 
 	// Set VSCode dark theme colors
@@ -61,19 +100,31 @@ func New() *App {
 
 	// Load configuration
 	cfg, err := config.Load()
-	if err != nil {
+	var configErr error
+	if errors.Is(err, config.ErrInvalidLintRules) {
+		configErr = err // Reported by Initialize
+	} else if err != nil {
 		// Continue without AI if config loading fails
 		fmt.Printf("Warning: Failed to load config: %v\n", err)
 		cfg = nil
 	}
 
+	linter := lint.Default()
+	if cfg != nil {
+		linter = cfg.Linter()
+	}
+
 	var aiClient *ai.Client
 	if cfg != nil {
-		aiClient = ai.NewClient(&cfg.AI)
+		if aiClient, err = ai.NewClient(&cfg.AI); err != nil {
+			configErr = fmt.Errorf("invalid AI redaction settings: %w", err)
+		}
 	}
 
 	return &App{
 		app:                 app,
+		options:             opts,
+		configErr:           configErr,
 		config:              cfg,
 		aiClient:            aiClient,
 		explorer:            ui.NewExplorer(app),
@@ -82,59 +133,131 @@ func New() *App {
 		currentResourceType: "all",
 		pages:               tview.NewPages(),
 		keyBindings:         navigation.GetDefaultKeyBindings(),
+		linter:              linter,
+		aiChats:             make(map[string]*ai.Chat),
 	}
 }
 
 func (a *App) Initialize() error {
+	if a.configErr != nil {
+		return a.configErr
+	}
+
+	offlineSchemas, err := validation.OfflineSources(a.options.Schemas)
+	if err != nil {
+		return fmt.Errorf("failed to load schemas: %w", err)
+	}
+	a.offlineSchemas = offlineSchemas
+
 	// Try to load Kubernetes config
-	kubeClient, err := kubernetes.NewUnifiedClient()
+	kubeClient, err := kubernetes.NewUnifiedClient(a.clientOptions(a.options.Context))
 	if err != nil {
 		fmt.Printf("Warning: Unable to load kubeconfig: %v\n", err)
 		a.currentNamespace = "default"
+		a.validator = a.newValidator(nil)
 	} else {
 		a.kubeClient = kubeClient
-		a.currentNamespace = "default" // Default namespace
+		a.informers = kubernetes.NewInformerManager(kubeClient)
+		a.validator = a.newValidator(kubeClient)
+		a.currentContext = kubeClient.CurrentContext()
+		a.currentNamespace = kubeClient.DefaultNamespace()
+	}
+
+	// An explicit --namespace wins over the context's default namespace
+	if a.options.Namespace != "" {
+		a.currentNamespace = a.options.Namespace
 	}
 
 	// Load namespaces
 	if a.kubeClient != nil {
-		namespaces, err := a.getNamespaces()
-		if err == nil {
-			a.namespaces = namespaces
-		}
+		a.loadNamespaces()
 	}
 
 	return nil
 }
 
+func (a *App) clientOptions(contextName string) kubernetes.ClientOptions {
+	return kubernetes.ClientOptions{
+		Kubeconfig: a.options.Kubeconfig,
+		Context:    contextName,
+	}
+}
+
+// Validate manifests against the offline schemas given on the command line
+// and the schemas the cluster serves. Without a cluster the newest bundled
+// schemas stand in, unless built-in kinds are covered by a schema directory
+// or version already. Objects are checked against the config's lint rules as
+// well, not the built-in ones. Nil if there are no schemas at all.
+func (a *App) newValidator(kubeClient *kubernetes.UnifiedClient) *validation.Validator {
+	sources := slices.Clone(a.offlineSchemas)
+	switch {
+	case kubeClient != nil:
+		sources = append(sources, validation.NewClusterSchemas(kubeClient.DiscoveryClient(), kubeClient.CRDClient(), validation.DefaultCacheDir()))
+	case a.options.Schemas.SchemaDir == "" && a.options.Schemas.KubeVersion == "":
+		if bundled := validation.NewestBundledSchemas(); bundled != nil {
+			sources = append(sources, bundled)
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	validator := validation.NewValidator(sources...)
+	if a.config != nil {
+		validator.SetLinter(a.config.ValidationLinter())
+	}
+	return validator
+}
+
+func (a *App) loadNamespaces() {
+	namespaces, err := a.getNamespaces()
+	if err == nil {
+		a.namespaces = namespaces
+	}
+}
+
 func (a *App) setupPages() {
 	// Set pages background color
 	a.pages.SetBackgroundColor(tcell.ColorBlack)
 
 	// Create pages
 	a.pages.AddPage("welcome", a.welcome.CreateWelcomeView(), true, true)
-	a.explorerList = a.explorer.CreateExplorerView(a.currentNamespace, a.currentResourceType)
-	a.pages.AddPage("explorer", a.explorerList, true, false)
+	a.explorerTable = a.explorer.CreateExplorerView(a.currentContext, a.currentNamespace, a.currentResourceType)
+	a.pages.AddPage("explorer", a.explorerTable, true, false)
 
 	// Load initial resources if connected
 	if a.kubeClient != nil {
 		go a.loadResources()
 	}
 
-	// Set up explorer list selection handler
-	a.explorerList.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
-		a.handleResourceSelection(mainText, secondaryText)
+	// Set up explorer table selection handler
+	a.explorerTable.SetSelectedFunc(func(row, column int) {
+		a.handleResourceSelection(a.explorer.SelectedRow(a.explorerTable))
 	})
 }
 
 func (a *App) setupKeyBindings() {
+	// Close functions of the modes whose views handle their own keys
+	closers := map[modes.Mode]func(){
+		modes.Logs:         a.closePodLogs,
+		modes.Apply:        a.closeApplyMode, // Leaves 'y' and 'f' to the apply view, Esc aborts
+		modes.Events:       a.closeNamespaceEvents,
+		modes.AIAnalysis:   a.closeAIAnalysis,   // Stops the request itself
+		modes.Deprecations: a.closeDeprecations, // Changes the target and scans files itself
+		modes.PortForwards: a.closePortForwards, // Handles 'd' itself
+	}
+
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		focused := a.app.GetFocus()
 		switch focused.(type) {
-		case *tview.InputField:
+		case *tview.InputField, *tview.DropDown:
 			return event // Let inputs handle their own input
 		}
 
+		// Forms and the edit diff handle their own keys, including Esc to cancel
+		if a.pages.HasPage("port-forward-form") || a.pages.HasPage("edit-diff") {
+			return event
+		}
+
 		// Check if help page is open first - if so, only handle help-related keys
 		if a.pages.HasPage("help") {
 			if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
@@ -144,6 +267,70 @@ func (a *App) setupKeyBindings() {
 			return nil
 		}
 
+		// The upload preview takes Enter and Esc itself
+		if a.pages.HasPage("ai-preview") {
+			return event
+		}
+
+		// Views that handle their own keys, only closing, quitting and help are global
+		if closeView, ok := closers[a.currentMode]; ok {
+			switch {
+			case event.Key() == tcell.KeyEsc:
+				closeView()
+				return nil
+			case event.Rune() == 'q':
+				a.app.Stop()
+				return nil
+			case event.Rune() == '?':
+				a.showHelpView()
+				return nil
+			}
+			return event
+		}
+
+		// The chat takes every key as question text, like the editor. Keys go
+		// to whatever covers it, e.g. the export prompt.
+		if a.currentMode == modes.AIChat {
+			if !a.aiChatView.HasFocus() {
+				return event
+			}
+			switch event.Key() {
+			case tcell.KeyEsc:
+				a.closeAIChat()
+				return nil
+			case tcell.KeyCtrlX:
+				a.stopAIChat()
+				return nil
+			case tcell.KeyCtrlL:
+				a.clearAIChat()
+				return nil
+			case tcell.KeyCtrlS:
+				a.showAIChatExportPrompt()
+				return nil
+			case tcell.KeyF1:
+				a.showHelpView()
+				return nil
+			}
+			return event
+		}
+
+		// The editor takes every key as text, only closing and help are global.
+		// Keys go to whatever covers the editor, e.g. the discard confirmation.
+		if a.currentMode == modes.Editor {
+			if !a.editor.HasFocus() {
+				return event
+			}
+			switch event.Key() {
+			case tcell.KeyEsc:
+				a.closeEditor()
+				return nil
+			case tcell.KeyF1:
+				a.showHelpView()
+				return nil
+			}
+			return event
+		}
+
 		if event.Key() == tcell.KeyEsc {
 			// Check if we're viewing resource details
 			if a.pages.HasPage("resource-details") {
@@ -165,6 +352,13 @@ func (a *App) setupKeyBindings() {
 			if a.currentMode == modes.Welcome {
 				a.currentMode = modes.Explorer
 				a.pages.SwitchToPage("explorer")
+			} else if a.currentMode == modes.Explorer {
+				a.editInExternalEditor()
+			}
+			return nil
+		case 'c':
+			if a.currentMode == modes.Explorer {
+				a.showContextSelector()
 			}
 			return nil
 		case 'n':
@@ -182,6 +376,51 @@ func (a *App) setupKeyBindings() {
 				a.performAIAnalysis()
 			}
 			return nil
+		case 'l':
+			if a.currentMode == modes.Explorer {
+				a.showPodLogs()
+			}
+			return nil
+		case 's':
+			if a.currentMode == modes.Explorer {
+				a.execIntoPod()
+			}
+			return nil
+		case 'f':
+			if a.currentMode == modes.Explorer {
+				a.showPortForwardForm()
+			}
+			return nil
+		case 'F':
+			if a.currentMode == modes.Explorer {
+				a.showPortForwards()
+			}
+			return nil
+		case 'E':
+			if a.currentMode == modes.Explorer {
+				a.showNamespaceEvents()
+			}
+			return nil
+		case 'A':
+			if a.currentMode == modes.Explorer {
+				a.showApplyFilePrompt()
+			}
+			return nil
+		case 'm':
+			if a.currentMode == modes.Explorer {
+				a.showEditorMenu()
+			}
+			return nil
+		case 'U':
+			if a.currentMode == modes.Explorer {
+				a.showDeprecations()
+			}
+			return nil
+		case 'C':
+			if a.currentMode == modes.Explorer {
+				a.showAIChat()
+			}
+			return nil
 		case '?':
 			a.showHelpView()
 			return nil
@@ -190,6 +429,57 @@ func (a *App) setupKeyBindings() {
 	})
 }
 
+func (a *App) showContextSelector() {
+	contexts, _, err := kubernetes.ListContexts(a.clientOptions(""))
+	if err != nil {
+		a.showErrorModal("Failed to load contexts", fmt.Sprintf("Error: %v", err))
+		return
+	}
+	if len(contexts) == 0 {
+		return
+	}
+
+	a.explorer.CreateContextSelector(contexts, a.pages, func(selectedContext string) {
+		a.switchContext(selectedContext)
+	})
+}
+
+// Rebuild the client for another kubeconfig context and reload the explorer
+func (a *App) switchContext(contextName string) {
+	a.stopExplorerWatches()
+	a.showExplorerMessage(fmt.Sprintf("Connecting to context %s...", contextName))
+
+	go func() {
+		kubeClient, err := kubernetes.NewUnifiedClient(a.clientOptions(contextName))
+		if err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Failed to switch context", fmt.Sprintf("Error: %v", err))
+				go a.loadResources()
+			})
+			return
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if a.informers != nil {
+				a.informers.Shutdown()
+			}
+			a.kubeClient = kubeClient
+			a.informers = kubernetes.NewInformerManager(kubeClient)
+			a.validator = a.newValidator(kubeClient)
+			a.deprecationTarget = ""
+			a.currentContext = kubeClient.CurrentContext()
+			a.currentNamespace = kubeClient.DefaultNamespace()
+			a.namespaces = nil
+			a.explorer.UpdateExplorerTitle(a.explorerTable, a.currentContext, a.currentNamespace, a.currentResourceType)
+
+			go func() {
+				a.loadNamespaces()
+				a.loadResources()
+			}()
+		})
+	}()
+}
+
 func (a *App) showNamespaceSelector() {
 	if len(a.namespaces) == 0 {
 		return
@@ -197,103 +487,136 @@ func (a *App) showNamespaceSelector() {
 
 	a.explorer.CreateNamespaceSelector(a.namespaces, a.pages, func(selectedNs string) {
 		a.currentNamespace = selectedNs
-		a.explorer.UpdateExplorerTitle(a.explorerList, a.currentNamespace, a.currentResourceType)
+		a.explorer.UpdateExplorerTitle(a.explorerTable, a.currentContext, a.currentNamespace, a.currentResourceType)
 		go a.loadResources()
 	})
 }
 
 func (a *App) showResourceSelector() {
-	a.explorer.CreateResourceSelector(a.pages, func(selectedResourceType string) {
+	resourceTypes := []string{"all"}
+	if a.kubeClient != nil {
+		resources, err := a.kubeClient.ListBrowsableResources()
+		if err != nil {
+			a.showErrorModal("Failed to load resource types", fmt.Sprintf("Error: %v", err))
+			return
+		}
+		resourceTypes = append(resourceTypes, resourceTypeNames(resources)...)
+	}
+
+	a.explorer.CreateResourceSelector(resourceTypes, a.pages, func(selectedResourceType string) {
 		a.currentResourceType = selectedResourceType
-		a.explorer.UpdateExplorerTitle(a.explorerList, a.currentNamespace, a.currentResourceType)
+		a.explorer.UpdateExplorerTitle(a.explorerTable, a.currentContext, a.currentNamespace, a.currentResourceType)
 		go a.loadResources()
 	})
 }
 
 func (a *App) loadResources() {
 	if a.kubeClient == nil {
-		a.explorerList.Clear()
-		a.explorerList.AddItem("Error: Unable to connect to Kubernetes", "", 0, nil)
-		a.app.Draw()
+		a.app.QueueUpdateDraw(func() {
+			a.stopExplorerWatches()
+			a.showExplorerMessage("Error: Unable to connect to Kubernetes")
+		})
 		return
 	}
 
-	// Clear the list
-	a.explorerList.Clear()
-
 	// Load resources based on current resource type filter
-	switch a.currentResourceType {
-	case "all":
-		a.loadAllResources()
-	case "pods", "services", "deployments", "configmaps", "secrets":
-		a.loadResourcesByType(a.currentResourceType)
-	default:
-		a.explorerList.AddItem(fmt.Sprintf("Resource type '%s' not yet implemented", a.currentResourceType), "", 0, nil)
+	resourceTypes := allResourceTypes
+	if a.currentResourceType != "all" {
+		resourceTypes = []string{a.currentResourceType}
 	}
 
-	a.app.Draw()
-}
-
-func (a *App) loadAllResources() {
-	resourceTypes := []string{"pods", "services", "deployments", "configmaps", "secrets"}
+	var loads []*resourceLoad
 	for _, resourceType := range resourceTypes {
-		a.loadResourcesByType(resourceType)
+		loads = append(loads, a.fetchResources(resourceType, a.currentNamespace))
 	}
-}
 
-func (a *App) loadResourcesByType(resourceType string) {
-	resources, err := a.getResourcesInNamespace(resourceType, a.currentNamespace)
-	if err != nil {
-		a.explorerList.AddItem(fmt.Sprintf("Error loading %s: %v", resourceType, err), "", 0, nil)
-	} else {
-		if len(resources) == 0 && a.currentResourceType == resourceType {
-			a.explorerList.AddItem(fmt.Sprintf("No %s found in this namespace", resourceType), "", 0, nil)
-		} else {
-			for _, resource := range resources {
-				displayText := fmt.Sprintf("%s: %s (%s)", resource.Type, resource.Name, resource.Status)
-				a.explorerList.AddItem(displayText, resource.Name, 0, nil)
-			}
-		}
-	}
-}
+	var generation int
+	a.app.QueueUpdateDraw(func() {
+		a.stopExplorerWatches()
+		a.explorerGeneration++
+		generation = a.explorerGeneration
 
-func (a *App) loadPods() {
-	a.loadResourcesByType("pods")
-}
+		a.explorerLoads = loads
+		a.renderExplorer(a.selectedExplorerRow())
+	})
 
-func (a *App) loadServices() {
-	a.loadResourcesByType("services")
+	// Keep the table live from here on
+	a.watchExplorerResources(generation, loads)
 }
 
 func (a *App) Run() error {
 	a.setupPages()
 	a.setupKeyBindings()
 
-	return a.app.SetRoot(a.pages, true).SetFocus(a.pages).Run()
+	// Client-go reports background errors, e.g. of forwarded connections,
+	// through klog, which would write over the UI
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	err := a.app.SetRoot(a.pages, true).SetFocus(a.pages).Run()
+	a.shutdown()
+	return err
 }
 
-func (a *App) handleResourceSelection(mainText string, resourceName string) {
-	if a.kubeClient == nil || resourceName == "" {
-		return
+// Release background watches once the UI has stopped
+func (a *App) shutdown() {
+	a.stopExplorerWatches()
+	a.stopPortForwards()
+	if a.logView != nil {
+		a.logView.Stop()
+	}
+	if a.aiCancel != nil {
+		a.aiCancel()
 	}
+	if a.aiChatCancel != nil {
+		a.aiChatCancel()
+	}
+	if a.informers != nil {
+		a.informers.Shutdown()
+	}
+}
 
-	// Parse resource type from mainText (format: "ResourceType: ResourceName (Status)")
-	parts := strings.Split(mainText, ":")
-	if len(parts) < 2 {
+func (a *App) handleResourceSelection(row *kubernetes.ResourceRow) {
+	if a.kubeClient == nil || row == nil {
 		return
 	}
-	resourceType := strings.TrimSpace(parts[0])
+	selected := *row
 
 	// Fetch resource details
 	go func() {
-		yamlContent, err := a.getResourceDetails(resourceType, resourceName, a.currentNamespace)
+		var yamlContent, description string
+		var lintFindings []lint.Finding
+		var obj unstructured.Unstructured
+		err := a.kubeClient.Get(context.Background(), selected.GVR, selected.Namespace, selected.Name, &obj)
+		if err == nil {
+			yamlContent, err = resourceYAML(obj)
+		}
 		if err != nil {
 			yamlContent = fmt.Sprintf("Error fetching resource details: %v", err)
+		} else {
+			// Selector matches and endpoints are best effort, the summary stands without them
+			related, _ := a.kubeClient.RelatedObjects(context.Background(), &obj)
+			description = ui.Describe(&obj, related)
+			lintFindings = a.linter.Lint(&obj, a.lintEnvironment(context.Background(), obj.GetNamespace()))
+		}
+
+		// Events about the object, e.g. scheduling failures or probe errors
+		var events []kubernetes.EventRecord
+		var eventsErr error
+		if selected.UID != "" {
+			events, eventsErr = a.kubeClient.ListObjectEvents(context.Background(), selected.Namespace, selected.UID)
 		}
 
 		// Create and show the details view
 		a.app.QueueUpdateDraw(func() {
-			rd := ui.NewResourceDetails(resourceName, resourceType, yamlContent)
+			rd := ui.NewResourceDetails(selected.Name, selected.Kind, yamlContent)
+			rd.SetDescription(description)
+			if lint.IsWorkload(&obj) || len(lintFindings) > 0 {
+				rd.SetLintFindings(lintFindings)
+			}
+			if selected.UID != "" {
+				rd.SetEvents(events, eventsErr)
+			}
 			detailsView := rd.CreateView()
 			a.pages.AddPage("resource-details", detailsView, true, true)
 			a.pages.SwitchToPage("resource-details")
@@ -301,6 +624,25 @@ func (a *App) handleResourceSelection(mainText string, resourceName string) {
 	}()
 }
 
+// What lint rules may look at in a namespace. Lookups are best effort, rules
+// needing what failed to load are skipped.
+func (a *App) lintEnvironment(ctx context.Context, namespace string) *lint.Environment {
+	env := &lint.Environment{}
+	if a.kubeClient == nil || namespace == "" {
+		return env
+	}
+
+	pdbGVR := schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}
+	var pdbList policyv1.PodDisruptionBudgetList
+	if err := a.kubeClient.List(ctx, pdbGVR, namespace, &pdbList); err == nil {
+		env.PodDisruptionBudgets = pdbList.Items
+		if env.PodDisruptionBudgets == nil {
+			env.PodDisruptionBudgets = []policyv1.PodDisruptionBudget{}
+		}
+	}
+	return env
+}
+
 // Helper methods using UnifiedClient GVR interface
 
 func (a *App) getNamespaces() ([]string, error) {
@@ -321,100 +663,64 @@ func (a *App) getNamespaces() ([]string, error) {
 	return namespaces, nil
 }
 
-func (a *App) getResourcesInNamespace(resourceType, namespace string) ([]Resource, error) {
+func (a *App) getResourcesInNamespace(resourceType, namespace string) (*kubernetes.ResourceTable, error) {
 	ctx := context.Background()
 
-	var gvr schema.GroupVersionResource
-	switch strings.ToLower(resourceType) {
-	case "pod", "pods":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-	case "service", "services":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
-	case "deployment", "deployments":
-		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
-	case "configmap", "configmaps":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
-	case "secret", "secrets":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
-	default:
-		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
-	}
-
-	var list unstructured.UnstructuredList
-	err := a.kubeClient.List(ctx, gvr, namespace, &list)
+	resourceInfo, err := a.kubeClient.ResolveResource(resourceType)
 	if err != nil {
 		return nil, err
 	}
 
-	var resources []Resource
-	for _, item := range list.Items {
-		name := item.GetName()
-		status := "Unknown"
+	// Cluster-scoped resources are listed regardless of the selected namespace
+	if !resourceInfo.Namespaced {
+		namespace = ""
+	}
 
-		// Extract status based on resource type
-		switch strings.ToLower(resourceType) {
-		case "pod", "pods":
-			if phase, found, _ := unstructured.NestedString(item.Object, "status", "phase"); found {
-				status = phase
-			}
-		case "service", "services":
-			if svcType, found, _ := unstructured.NestedString(item.Object, "spec", "type"); found {
-				status = svcType
-			}
-		case "deployment", "deployments":
-			if replicas, found, _ := unstructured.NestedInt64(item.Object, "status", "replicas"); found {
-				if readyReplicas, readyFound, _ := unstructured.NestedInt64(item.Object, "status", "readyReplicas"); readyFound {
-					status = fmt.Sprintf("%d/%d", readyReplicas, replicas)
-				} else {
-					status = fmt.Sprintf("0/%d", replicas)
-				}
-			}
-		}
+	return a.kubeClient.ListTable(ctx, resourceInfo.GVR, namespace)
+}
 
-		resources = append(resources, Resource{
-			Type:   item.GetKind(),
-			Name:   name,
-			Status: status,
-		})
+// Selector names for browsable resources: the plural name, qualified with the
+// group when another group serves the same plural (e.g. "events.events.k8s.io")
+func resourceTypeNames(resources []kubernetes.ResourceInfo) []string {
+	pluralCounts := make(map[string]int)
+	for _, resource := range resources {
+		pluralCounts[resource.GVR.Resource]++
+	}
+
+	var names []string
+	for _, resource := range resources {
+		name := resource.GVR.Resource
+		if pluralCounts[name] > 1 && resource.GVR.Group != "" {
+			name += "." + resource.GVR.Group
+		}
+		names = append(names, name)
 	}
 
-	return resources, nil
+	return names
 }
 
-func (a *App) getPodsInNamespace(namespace string) ([]Resource, error) {
+func (a *App) getPodsInNamespace(namespace string) (*kubernetes.ResourceTable, error) {
 	return a.getResourcesInNamespace("pods", namespace)
 }
 
-func (a *App) getServicesInNamespace(namespace string) ([]Resource, error) {
+func (a *App) getServicesInNamespace(namespace string) (*kubernetes.ResourceTable, error) {
 	return a.getResourcesInNamespace("services", namespace)
 }
 
-func (a *App) getResourceDetails(resourceType, resourceName, namespace string) (string, error) {
+func (a *App) getResourceDetails(gvr schema.GroupVersionResource, namespace, resourceName string) (string, error) {
 	ctx := context.Background()
 
-	var gvr schema.GroupVersionResource
-	switch strings.ToLower(resourceType) {
-	case "pod":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-	case "service":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
-	case "deployment":
-		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
-	case "configmap":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
-	case "secret":
-		gvr = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
-	default:
-		return "", fmt.Errorf("unsupported resource type: %s", resourceType)
-	}
-
 	var obj unstructured.Unstructured
 	err := a.kubeClient.Get(ctx, gvr, namespace, resourceName, &obj)
 	if err != nil {
 		return "", err
 	}
 
-	// Convert to YAML for display
+	return resourceYAML(obj)
+}
+
+// Convert to YAML for display
+func resourceYAML(obj unstructured.Unstructured) (string, error) {
 	obj = kubernetes.CleanData(obj)
 	yamlBytes, err := yaml.Marshal(obj.Object)
 	if err != nil {
@@ -440,7 +746,11 @@ func (a *App) showHelpView() {
 			case tcell.KeyEnter:
 				keyStr = "Enter"
 			default:
-				keyStr = fmt.Sprintf("Key:%d", binding.Key)
+				if name, ok := tcell.KeyNames[binding.Key]; ok {
+					keyStr = name
+				} else {
+					keyStr = fmt.Sprintf("Key:%d", binding.Key)
+				}
 			}
 		} else if binding.Rune != 0 {
 			keyStr = string(binding.Rune)
@@ -476,81 +786,6 @@ func (a *App) showHelpView() {
 	a.pages.AddPage("help", flex, true, true)
 }
 
-func (a *App) performAIAnalysis() {
-	if a.aiClient == nil {
-		a.showErrorModal("AI not configured", "AI analysis is not available. Please configure AI settings in ~/.config/kubeguide/config.yaml or set environment variables.")
-		return
-	}
-
-	// Get currently selected resource
-	currentItem := a.explorerList.GetCurrentItem()
-	if currentItem < 0 {
-		a.showErrorModal("No selection", "Please select a resource to analyze.")
-		return
-	}
-
-	mainText, resourceName := a.explorerList.GetItemText(currentItem)
-	if resourceName == "" {
-		a.showErrorModal("Invalid selection", "Please select a valid resource.")
-		return
-	}
-
-	// Parse resource type from mainText (format: "ResourceType: ResourceName (Status)")
-	parts := strings.Split(mainText, ":")
-	if len(parts) < 2 {
-		a.showErrorModal("Invalid resource", "Unable to determine resource type.")
-		return
-	}
-	resourceType := strings.TrimSpace(parts[0])
-
-	// Only analyze pods for now
-	if strings.ToLower(resourceType) != "pod" {
-		a.showErrorModal("Unsupported resource", "AI analysis is currently only supported for pods.")
-		return
-	}
-
-	// Check if pod is in a failed state
-	if !strings.Contains(strings.ToLower(mainText), "failed") &&
-		!strings.Contains(strings.ToLower(mainText), "error") &&
-		!strings.Contains(strings.ToLower(mainText), "crashloopbackoff") &&
-		!strings.Contains(strings.ToLower(mainText), "imagepullbackoff") {
-		a.showInfoModal("Pod status", "AI analysis is most useful for failed or problematic pods. This pod appears to be running normally.")
-		return
-	}
-
-	// Show loading modal
-	a.showLoadingModal("Analyzing pod with AI...")
-
-	// Get pod YAML in background
-	go func() {
-		ctx := context.Background()
-		yamlContent, err := a.getResourceDetails(resourceType, resourceName, a.currentNamespace)
-		if err != nil {
-			a.app.QueueUpdateDraw(func() {
-				a.pages.RemovePage("loading")
-				a.showErrorModal("Failed to get pod details", fmt.Sprintf("Error: %v", err))
-			})
-			return
-		}
-
-		// Analyze with AI
-		analysis, err := a.aiClient.AnalyzePod(ctx, yamlContent)
-		if err != nil {
-			a.app.QueueUpdateDraw(func() {
-				a.pages.RemovePage("loading")
-				a.showErrorModal("AI analysis failed", fmt.Sprintf("Error: %v", err))
-			})
-			return
-		}
-
-		// Show results
-		a.app.QueueUpdateDraw(func() {
-			a.pages.RemovePage("loading")
-			a.showAIAnalysisResults(resourceName, analysis)
-		})
-	}()
-}
-
 func (a *App) showErrorModal(title, message string) {
 	modal := tview.NewModal().
 		SetText(message).
@@ -564,6 +799,33 @@ func (a *App) showErrorModal(title, message string) {
 	a.pages.AddPage("error-modal", modal, false, true)
 }
 
+// Ask for a line of text in a bordered input field on page, starting with
+// text. onDone gets what was entered on Enter, Esc or an empty field cancel.
+func (a *App) showInputPrompt(page, title, label, text string, onDone func(text string)) {
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(text).
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabelColor(tcell.ColorLightBlue)
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage(page)
+		if key == tcell.KeyEnter && input.GetText() != "" {
+			onDone(input.GetText())
+		}
+	})
+	input.SetBorder(true).
+		SetTitle(title).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitleColor(tcell.ColorWhite)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 3, 0, true).
+		AddItem(nil, 0, 1, false)
+	a.pages.AddPage(page, flex, true, true)
+}
+
 func (a *App) showInfoModal(title, message string) {
 	modal := tview.NewModal().
 		SetText(message).
@@ -580,82 +842,3 @@ func (a *App) showInfoModal(title, message string) {
 	modal.SetTitle(title)
 	a.pages.AddPage("info-modal", modal, false, true)
 }
-
-func (a *App) showLoadingModal(message string) {
-	modal := tview.NewModal().
-		SetText(message).
-		SetBackgroundColor(tcell.ColorBlack).
-		SetTextColor(tcell.ColorWhite)
-	a.pages.AddPage("loading", modal, false, true)
-}
-
-func (a *App) showAIAnalysisResults(resourceName, analysis string) {
-	textView := tview.NewTextView().
-		SetText(analysis).
-		SetTextAlign(tview.AlignLeft).
-		SetDynamicColors(true).
-		SetWrap(true).
-		SetScrollable(true)
-
-	textView.SetBackgroundColor(tcell.ColorBlack)
-	textView.SetTextColor(tcell.ColorWhite)
-	textView.SetBorder(true).SetTitle(fmt.Sprintf(" AI Analysis: %s - Press 'esc' to close ", resourceName))
-
-	// Allow closing with Esc
-	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyEsc {
-			a.pages.RemovePage("ai-analysis")
-			return nil
-		}
-		return event
-	})
-
-	a.pages.AddPage("ai-analysis", textView, true, true)
-}
-
-func (a *App) performAIAnalysisForce() {
-	// This is a simplified version that skips the health check
-	currentItem := a.explorerList.GetCurrentItem()
-	if currentItem < 0 {
-		return
-	}
-
-	mainText, resourceName := a.explorerList.GetItemText(currentItem)
-	parts := strings.Split(mainText, ":")
-	if len(parts) < 2 {
-		return
-	}
-	resourceType := strings.TrimSpace(parts[0])
-
-	if strings.ToLower(resourceType) != "pod" {
-		return
-	}
-
-	a.showLoadingModal("Analyzing pod with AI...")
-
-	go func() {
-		ctx := context.Background()
-		yamlContent, err := a.getResourceDetails(resourceType, resourceName, a.currentNamespace)
-		if err != nil {
-			a.app.QueueUpdateDraw(func() {
-				a.pages.RemovePage("loading")
-				a.showErrorModal("Failed to get pod details", fmt.Sprintf("Error: %v", err))
-			})
-			return
-		}
-
-		analysis, err := a.aiClient.AnalyzePod(ctx, yamlContent)
-		if err != nil {
-			a.app.QueueUpdateDraw(func() {
-				a.pages.RemovePage("loading")
-				a.showErrorModal("AI analysis failed", fmt.Sprintf("Error: %v", err))
-			})
-			return
-		}
-
-		a.app.QueueUpdateDraw(func() {
-			a.pages.RemovePage("loading")
-			a.showAIAnalysisResults(resourceName, analysis)
-		})
-	}()
-}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Ask for the path of a manifest to apply
+func (a *App) showApplyFilePrompt() {
+	if a.kubeClient == nil {
+		return
+	}
+
+	a.showInputPrompt("apply-file-prompt", " Apply Manifest (Enter to preview, Esc to cancel) ", "Manifest file: ", "", func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			a.showErrorModal("Failed to read manifest", fmt.Sprintf("Error: %v", err))
+			return
+		}
+		a.openApplyMode(path, data, nil)
+	})
+}
+
+// Preview applying a manifest with a server dry run and apply it once
+// confirmed. onClose runs when the user leaves Apply mode, e.g. to return to
+// the editor the manifest came from; nil returns to the explorer.
+func (a *App) openApplyMode(source string, data []byte, onClose func()) {
+	objects, err := kubernetes.ParseManifests(data)
+	if err != nil {
+		a.showErrorModal("Invalid manifest", fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	a.applyOnClose = onClose
+	a.applyView = ui.NewApplyView(source, a.confirmApply, func(force bool) {
+		a.planApply(objects, force)
+	})
+	a.pages.AddPage("apply", a.applyView.CreateView(), true, true)
+	a.currentMode = modes.Apply
+
+	a.planApply(objects, false)
+}
+
+func (a *App) planApply(objects []*unstructured.Unstructured, force bool) {
+	view := a.applyView
+	view.SetStatus("running server dry run...")
+	namespace := a.currentNamespace
+
+	go func() {
+		plan := a.kubeClient.PlanApply(context.Background(), objects, namespace, kubernetes.ApplyOptions{Force: force})
+		a.app.QueueUpdateDraw(func() {
+			if view != a.applyView {
+				return // Apply mode was left meanwhile
+			}
+			view.SetPlan(plan, force)
+			a.applyPlan, a.applyForce = plan, force
+		})
+	}()
+}
+
+func (a *App) confirmApply() {
+	if a.applyCancel != nil {
+		return // Already applying
+	}
+	pending := a.applyView.Pending()
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Apply %d changed object(s) to context %s?", pending, a.currentContext)).
+		AddButtons([]string{"Apply all", "Abort all"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.pages.RemovePage("apply-confirm")
+			if buttonLabel == "Apply all" {
+				a.runApply()
+			} else {
+				a.closeApplyMode()
+			}
+		})
+	modal.SetBackgroundColor(tcell.ColorBlack)
+	modal.SetTextColor(tcell.ColorWhite)
+	a.pages.AddPage("apply-confirm", modal, false, true)
+}
+
+// Apply every object the dry run would create or change, in manifest order
+func (a *App) runApply() {
+	view, plan, force := a.applyView, a.applyPlan, a.applyForce
+	view.SetStatus("applying...")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.applyCancel = cancel
+
+	go func() {
+		defer cancel()
+
+		applied, failed := 0, 0
+		for i, item := range plan {
+			if item.Action != kubernetes.ApplyCreate && item.Action != kubernetes.ApplyUpdate {
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			_, err := a.kubeClient.Apply(ctx, item.GVR, item.Namespace, item.Object, kubernetes.ApplyOptions{Force: force})
+			if err != nil {
+				failed++
+			} else {
+				applied++
+			}
+			a.app.QueueUpdateDraw(func() {
+				view.SetResult(i, err)
+			})
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			view.SetStatus(fmt.Sprintf("applied %d, failed %d", applied, failed))
+			if view == a.applyView {
+				a.applyCancel = nil
+			}
+		})
+	}()
+}
+
+// Leave Apply mode, stopping an apply that is still running
+func (a *App) closeApplyMode() {
+	if a.applyCancel != nil {
+		a.applyCancel()
+		a.applyCancel = nil
+	}
+	a.applyView = nil
+	a.applyPlan = nil
+	a.pages.RemovePage("apply-confirm")
+	a.pages.RemovePage("apply")
+
+	onClose := a.applyOnClose
+	a.applyOnClose = nil
+	if onClose != nil {
+		onClose()
+		return
+	}
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"kubeguide/internal/ai"
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Chat with the AI about the resource selected in the explorer. Each resource
+// keeps its conversation until kubeguide exits.
+func (a *App) showAIChat() {
+	if a.aiClient == nil {
+		a.showErrorModal("AI not configured", "AI chat is not available. Please configure AI settings in ~/.config/kubeguide/config.yaml or set environment variables.")
+		return
+	}
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if row == nil {
+		a.showErrorModal("No selection", "Please select a resource to chat about.")
+		return
+	}
+	selected := *row
+
+	key := a.aiChatKey(selected)
+	if chat, ok := a.aiChats[key]; ok {
+		a.openAIChat(chat)
+		return
+	}
+
+	// The YAML seeding the conversation is fetched once per resource
+	go func() {
+		yamlContent, err := a.getResourceDetails(selected.GVR, selected.Namespace, selected.Name)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showErrorModal("Failed to get resource details", fmt.Sprintf("Error: %v", err))
+				return
+			}
+			resource := fmt.Sprintf("%s %s", selected.Kind, selected.Name)
+			if selected.Namespace != "" {
+				resource = fmt.Sprintf("%s %s/%s", selected.Kind, selected.Namespace, selected.Name)
+			}
+			chat := ai.NewResourceChat(resource, yamlContent)
+			a.aiChats[key] = chat
+			a.openAIChat(chat)
+		})
+	}()
+}
+
+func (a *App) aiChatKey(row kubernetes.ResourceRow) string {
+	return strings.Join([]string{a.currentContext, row.GVR.String(), row.Namespace, row.Name}, "/")
+}
+
+func (a *App) openAIChat(chat *ai.Chat) {
+	var view *ui.ChatView
+	view = ui.NewChatView(a.app, chat.Resource, func(question string) {
+		a.askAIChat(chat, view, question)
+	})
+	view.Render(chat.Messages, "", "")
+
+	a.aiChat, a.aiChatView = chat, view
+	a.pages.AddPage("ai-chat", view.Primitive(), true, true)
+	a.currentMode = modes.AIChat
+}
+
+func (a *App) askAIChat(chat *ai.Chat, view *ui.ChatView, question string) {
+	if a.aiChatCancel != nil {
+		view.SetStatus("Still answering (Press Ctrl-X to stop)")
+		return
+	}
+
+	req := a.aiClient.PrepareQuestion(chat, question)
+	if !a.aiClient.ConfirmUpload() {
+		a.sendAIChat(chat, view, question, req)
+		return
+	}
+	view.SetStatus("Waiting for confirmation")
+	a.showAIPreview(req, func() {
+		a.sendAIChat(chat, view, question, req)
+	}, func() {
+		view.SetQuestion(question)
+		view.SetStatus("Not sent")
+	})
+}
+
+// Stream the answer to question, sent as req, into the chat
+func (a *App) sendAIChat(chat *ai.Chat, view *ui.ChatView, question string, req ai.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.aiChatCancel = cancel
+
+	partial := ""
+	view.Render(chat.Messages, question, partial)
+	view.SetStatus("Answering... (Press Ctrl-X to stop)")
+
+	go func() {
+		defer cancel()
+
+		// Updates are queued in order, so the last text arrives before the result
+		answer, err := a.aiClient.Send(ctx, req, func(text string) {
+			a.app.QueueUpdateDraw(func() {
+				partial += text
+				if a.aiChatView == view {
+					view.Render(chat.Messages, question, partial)
+				}
+			})
+		})
+
+		a.app.QueueUpdateDraw(func() {
+			// A partial answer is kept, it may be all that was needed. Empty
+			// ones are not, some APIs reject empty assistant messages.
+			if answer != "" {
+				chat.Record(question, answer)
+			}
+			if a.aiChatView != view {
+				return // Closed meanwhile
+			}
+			a.aiChatCancel = nil
+			view.Render(chat.Messages, "", "")
+
+			switch {
+			case err == nil && answer == "":
+				view.SetStatus("No answer")
+			case err == nil:
+				view.SetStatus("")
+			case errors.Is(err, context.Canceled):
+				view.SetStatus("Stopped")
+			default:
+				view.SetStatus("Failed")
+				a.showErrorModal("AI chat failed", fmt.Sprintf("Error: %v", err))
+			}
+		})
+	}()
+}
+
+func (a *App) stopAIChat() {
+	if a.aiChatCancel != nil {
+		a.aiChatCancel()
+	}
+}
+
+func (a *App) clearAIChat() {
+	if a.aiChatCancel != nil {
+		return
+	}
+	a.aiChat.Clear()
+	a.aiChatView.Render(a.aiChat.Messages, "", "")
+	a.aiChatView.SetStatus("Cleared")
+}
+
+// Ask where to save the transcript as markdown
+func (a *App) showAIChatExportPrompt() {
+	chat, view := a.aiChat, a.aiChatView
+	name := strings.NewReplacer(" ", "-", "/", "-", ":", "-").Replace(strings.ToLower(chat.Resource))
+
+	a.showInputPrompt("ai-chat-export-prompt", " Export Chat (Enter to save, Esc to cancel) ", "Save transcript to: ", name+"-chat.md", func(path string) {
+		if err := os.WriteFile(path, []byte(chat.Markdown()), 0644); err != nil {
+			a.showErrorModal("Failed to export transcript", fmt.Sprintf("Error: %v", err))
+			return
+		}
+		view.SetStatus("Exported to " + path)
+	})
+}
+
+// Close the chat, stopping an answer in progress. The conversation stays.
+func (a *App) closeAIChat() {
+	a.stopAIChat()
+	a.aiChatCancel = nil
+	a.aiChat, a.aiChatView = nil, nil
+	a.pages.RemovePage("ai-chat")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
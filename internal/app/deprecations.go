@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Report of deprecated and removed API versions used by live objects and by
+// manifest files, for planning an upgrade to the target version
+func (a *App) showDeprecations() {
+	table := ui.NewListTable("")
+	table.SetCell(0, 0, tview.NewTableCell("Scanning...").SetSelectable(false))
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '[', ']', 'R':
+			// Nothing to change or rescan until the server version is known
+			if a.deprecationTarget == "" {
+				return nil
+			}
+		}
+		switch event.Rune() {
+		case '[':
+			a.deprecationTarget = kubernetes.NextKubeVersion(a.deprecationTarget, -1)
+		case ']':
+			a.deprecationTarget = kubernetes.NextKubeVersion(a.deprecationTarget, 1)
+		case 'f':
+			a.showDeprecationFilePrompt(table)
+			return nil
+		case 'R':
+		default:
+			return event
+		}
+		a.loadDeprecations(table)
+		return nil
+	})
+
+	a.pages.AddPage("deprecations", table, true, true)
+	a.currentMode = modes.Deprecations
+
+	if a.deprecationTarget != "" {
+		a.loadDeprecations(table)
+		return
+	}
+	// Upgrades go one minor version at a time, so look at the next one
+	go func() {
+		target := kubernetes.NewestRemovalVersion()
+		if a.kubeClient != nil {
+			if version, err := a.kubeClient.ServerVersion(); err == nil {
+				target = kubernetes.NextKubeVersion(version, 1)
+			}
+		}
+		a.app.QueueUpdateDraw(func() {
+			a.deprecationTarget = target
+			a.loadDeprecations(table)
+		})
+	}()
+}
+
+// Scan for the current target, stopping the scan for the previous one
+func (a *App) loadDeprecations(table *tview.Table) {
+	target, files := a.deprecationTarget, a.deprecationFiles
+	table.SetTitle(fmt.Sprintf(" Deprecated APIs - Target: %s (Press '['/']' to change target, 'f' to scan files, 'R' to rescan, Esc to return) ", target))
+
+	a.stopDeprecationScan()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.deprecationCancel = cancel
+	a.deprecationScan++
+	scan := a.deprecationScan
+
+	go func() {
+		defer cancel()
+
+		var findings []kubernetes.DeprecationFinding
+		var errs []error
+		if a.kubeClient != nil {
+			live, err := a.kubeClient.ScanDeprecations(ctx, target)
+			findings = append(findings, live...)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, path := range files {
+			found, err := scanDeprecatedManifests(path, target)
+			findings = append(findings, found...)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		kubernetes.SortDeprecationFindings(findings)
+
+		a.app.QueueUpdateDraw(func() {
+			if scan == a.deprecationScan {
+				ui.RenderDeprecations(table, findings, errors.Join(errs...))
+			}
+		})
+	}()
+}
+
+func (a *App) stopDeprecationScan() {
+	if a.deprecationCancel != nil {
+		a.deprecationCancel()
+		a.deprecationCancel = nil
+	}
+}
+
+// Ask for a manifest file or directory to include in the report
+func (a *App) showDeprecationFilePrompt(table *tview.Table) {
+	a.showInputPrompt("deprecations-file-prompt", " Scan Manifests (Enter to scan, Esc to cancel) ", "File or directory: ", "", func(path string) {
+		if _, err := os.Stat(path); err != nil {
+			a.showErrorModal("Failed to read manifests", fmt.Sprintf("Error: %v", err))
+			return
+		}
+		a.deprecationFiles = append(a.deprecationFiles, path)
+		// Without a target yet, the first scan includes the file
+		if a.deprecationTarget != "" {
+			a.loadDeprecations(table)
+		}
+	})
+}
+
+func (a *App) closeDeprecations() {
+	a.stopDeprecationScan()
+	a.deprecationScan++
+	a.pages.RemovePage("deprecations")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
+
+// Scan a manifest file, or the YAML and JSON files below a directory
+func scanDeprecatedManifests(path, target string) ([]kubernetes.DeprecationFinding, error) {
+	var findings []kubernetes.DeprecationFinding
+	var errs []error
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		// A file given directly is scanned whatever its extension
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			if file != path {
+				return nil
+			}
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		found, err := kubernetes.ScanManifestDeprecations(file, data, target)
+		findings = append(findings, found...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return findings, errors.Join(errs...)
+}
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/ui"
+)
+
+// Written above the object. Leading comment lines are dropped when the file
+// is read back, so errors annotated there don't end up in the object.
+const externalEditHeader = `# Please edit the object below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this
+# file will be reopened with the relevant failures.
+#
+`
+
+// Edit of one object in the user's editor, kept across reopening the editor
+// after a failed update
+type externalEdit struct {
+	row      kubernetes.ResourceRow
+	live     *unstructured.Unstructured // Object as last read from the server, the base of the diff
+	text     string                     // Manifest as last written by the editor
+	problems []string                   // Why the last attempt failed, annotated at the top of the file
+}
+
+// Edit the selected resource in $KUBE_EDITOR or $EDITOR like kubectl edit:
+// show the diff once the editor exits and update the object
+func (a *App) editInExternalEditor() {
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if a.kubeClient == nil || row == nil {
+		a.showErrorModal("No selection", "Please select a resource to edit.")
+		return
+	}
+	selected := *row
+
+	go func() {
+		live := &unstructured.Unstructured{}
+		err := a.kubeClient.Get(context.Background(), selected.GVR, selected.Namespace, selected.Name, live)
+		var text string
+		if err == nil {
+			// Keeps resourceVersion, so the update fails if someone else changed the object meanwhile
+			text, err = resourceYAML(*live.DeepCopy())
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showErrorModal("Failed to get resource", fmt.Sprintf("Error: %v", err))
+				return
+			}
+			a.runExternalEdit(&externalEdit{row: selected, live: live, text: text})
+		})
+	}()
+}
+
+// Suspend the UI while the editor runs, then show what changed. Must run on
+// the UI goroutine.
+func (a *App) runExternalEdit(edit *externalEdit) {
+	var edited *unstructured.Unstructured
+	var err error
+	a.app.Suspend(func() {
+		edited, err = edit.run()
+	})
+	if err != nil {
+		a.showErrorModal("Edit failed", fmt.Sprintf("Error: %v", err))
+		return
+	}
+	if edited == nil {
+		a.showEditMessage("Edit cancelled, the file was empty.")
+		return
+	}
+
+	before, err := resourceYAML(*edit.live.DeepCopy())
+	if err != nil {
+		a.showErrorModal("Edit failed", fmt.Sprintf("Error: %v", err))
+		return
+	}
+	afterBytes, err := yaml.Marshal(edited.Object)
+	if err != nil {
+		a.showErrorModal("Edit failed", fmt.Sprintf("Error: %v", err))
+		return
+	}
+	if before == string(afterBytes) {
+		a.showEditMessage("Edit cancelled, no changes made.")
+		return
+	}
+
+	a.showExternalEditDiff(edit, edited, ui.RenderDiff(before, string(afterBytes)))
+}
+
+// Confirm the update with the diff against the live object
+func (a *App) showExternalEditDiff(edit *externalEdit, edited *unstructured.Unstructured, diffText string) {
+	textView := tview.NewTextView().
+		SetText(diffText).
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetScrollable(true)
+	textView.SetBackgroundColor(tcell.ColorBlack)
+	textView.SetTextColor(tcell.ColorWhite)
+	textView.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Edit %s: apply(y) edit again(e) cancel(Esc) ", edit.objectName())).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitleColor(tcell.ColorWhite)
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			a.pages.RemovePage("edit-diff")
+		case event.Rune() == 'y':
+			a.pages.RemovePage("edit-diff")
+			a.updateExternalEdit(edit, edited)
+		case event.Rune() == 'e':
+			a.pages.RemovePage("edit-diff")
+			a.runExternalEdit(edit)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	a.pages.AddPage("edit-diff", textView, true, true)
+}
+
+// Replace the object with the edited one. Conflicts and validation errors
+// reopen the editor with the error annotated.
+func (a *App) updateExternalEdit(edit *externalEdit, edited *unstructured.Unstructured) {
+	go func() {
+		ctx := context.Background()
+		err := a.kubeClient.Update(ctx, edit.row.GVR, edit.row.Namespace, edited)
+		if err == nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showEditMessage(fmt.Sprintf("%s edited.", edit.objectName()))
+			})
+			return
+		}
+		if !kubernetes.IsConflict(err) && !kubernetes.IsInvalid(err) {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Update failed", fmt.Sprintf("Error: %v", err))
+			})
+			return
+		}
+
+		edit.problems = strings.Split(err.Error(), "\n")
+		if kubernetes.IsConflict(err) {
+			// Rebase the edit on the latest object, the next diff shows what changed meanwhile
+			latest := &unstructured.Unstructured{}
+			if getErr := a.kubeClient.Get(ctx, edit.row.GVR, edit.row.Namespace, edit.row.Name, latest); getErr != nil {
+				a.app.QueueUpdateDraw(func() {
+					a.showErrorModal("Update failed", fmt.Sprintf("Error: %v\n\nReloading the object failed: %v", err, getErr))
+				})
+				return
+			}
+			edited.SetResourceVersion(latest.GetResourceVersion())
+			if text, marshalErr := yaml.Marshal(edited.Object); marshalErr == nil {
+				edit.text = string(text)
+			}
+			edit.live = latest
+			edit.problems = append(edit.problems, "The object was changed by someone else. Your changes now apply to the latest version, check the diff before applying again.")
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			a.runExternalEdit(edit)
+		})
+	}()
+}
+
+func (a *App) showEditMessage(message string) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.pages.RemovePage("edit-message")
+		})
+	modal.SetBackgroundColor(tcell.ColorBlack)
+	modal.SetTextColor(tcell.ColorWhite)
+	a.pages.AddPage("edit-message", modal, false, true)
+}
+
+// Kind and namespaced name, e.g. "Deployment default/web"
+func (e *externalEdit) objectName() string {
+	name := e.row.Name
+	if e.row.Namespace != "" {
+		name = e.row.Namespace + "/" + name
+	}
+	return e.row.Kind + " " + name
+}
+
+// Open the editor until the file parses as the edited object, returning
+// nil when the user emptied the file
+func (e *externalEdit) run() (*unstructured.Unstructured, error) {
+	for {
+		if err := e.editFile(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(e.text) == "" {
+			return nil, nil
+		}
+
+		obj, err := e.parse()
+		if err == nil {
+			e.problems = nil
+			return obj, nil
+		}
+		e.problems = strings.Split(err.Error(), "\n")
+	}
+}
+
+// Let the user edit the text in a temporary file
+func (e *externalEdit) editFile() error {
+	file, err := os.CreateTemp("", "kubeguide-edit-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	var content strings.Builder
+	content.WriteString(externalEditHeader)
+	if len(e.problems) > 0 {
+		fmt.Fprintf(&content, "# %s was not updated:\n", e.objectName())
+		for _, problem := range e.problems {
+			fmt.Fprintf(&content, "# * %s\n", problem)
+		}
+		content.WriteString("#\n")
+	}
+	content.WriteString(e.text)
+
+	_, err = file.WriteString(content.String())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", externalEditor()+` "$1"`, "sh", file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", externalEditor(), err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return err
+	}
+	e.text = stripLeadingComments(string(data))
+	return nil
+}
+
+// Decode the edited text, which must still be the same object
+func (e *externalEdit) parse() (*unstructured.Unstructured, error) {
+	objects, err := kubernetes.ParseManifests([]byte(e.text))
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) != 1 {
+		return nil, fmt.Errorf("expected one object, found %d", len(objects))
+	}
+
+	obj := objects[0]
+	if obj.GetKind() != e.live.GetKind() || obj.GetName() != e.live.GetName() {
+		return nil, fmt.Errorf("kind and metadata.name cannot be changed, expected %s %s", e.live.GetKind(), e.live.GetName())
+	}
+	if obj.GetNamespace() != e.live.GetNamespace() {
+		return nil, fmt.Errorf("metadata.namespace cannot be changed, expected %q", e.live.GetNamespace())
+	}
+	return obj, nil
+}
+
+// The user's editor, kubectl's $KUBE_EDITOR taking precedence over $EDITOR
+func externalEditor() string {
+	for _, name := range []string{"KUBE_EDITOR", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// Drop the comment block at the top of the file: the header and any errors
+// annotated from the previous attempt
+func stripLeadingComments(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#") {
+			return strings.Join(lines[i:], "")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+	"kubeguide/internal/validation"
+)
+
+// Choose how to start editing: a blank manifest, a template or the selected resource
+func (a *App) showEditorMenu() {
+	row := a.explorer.SelectedRow(a.explorerTable)
+
+	buttons := []string{"New manifest", "From template"}
+	if a.kubeClient != nil && row != nil {
+		buttons = append(buttons, "Edit selected")
+	}
+	buttons = append(buttons, "Cancel")
+
+	modal := tview.NewModal().
+		SetText("Open the manifest editor with").
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.pages.RemovePage("editor-menu")
+			switch buttonLabel {
+			case "New manifest":
+				a.openEditor("new manifest", "manifest.yaml", "")
+			case "From template":
+				a.showTemplateSelector()
+			case "Edit selected":
+				a.editSelectedResource(*row)
+			}
+		})
+	modal.SetBackgroundColor(tcell.ColorBlack)
+	modal.SetTextColor(tcell.ColorWhite)
+	a.pages.AddPage("editor-menu", modal, false, true)
+}
+
+func (a *App) showTemplateSelector() {
+	templates := make(map[string]string)
+	var names []string
+	for _, template := range kubernetes.ManifestTemplates {
+		templates[template.Name] = template.Manifest
+		names = append(names, template.Name)
+	}
+
+	a.explorer.CreateTemplateSelector(names, a.pages, func(name string) {
+		// The selector switches back to the explorer after this returns
+		go a.app.QueueUpdateDraw(func() {
+			a.openEditor("template "+name, "manifest.yaml", templates[name])
+		})
+	})
+}
+
+// Open the selected resource without status and server-maintained metadata
+func (a *App) editSelectedResource(selected kubernetes.ResourceRow) {
+	go func() {
+		yamlContent, err := a.getResourceDetails(selected.GVR, selected.Namespace, selected.Name)
+		if err == nil {
+			yamlContent, err = editableYAML(yamlContent)
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showErrorModal("Failed to get resource", fmt.Sprintf("Error: %v", err))
+				return
+			}
+			name := selected.Name
+			if selected.Namespace != "" {
+				name = selected.Namespace + "/" + name
+			}
+			fileName := strings.ToLower(selected.Kind) + "-" + selected.Name + ".yaml"
+			a.openEditor(selected.Kind+" "+name, fileName, yamlContent)
+		})
+	}()
+}
+
+func editableYAML(yamlContent string) (string, error) {
+	var obj unstructured.Unstructured
+	if err := yaml.Unmarshal([]byte(yamlContent), &obj.Object); err != nil {
+		return "", err
+	}
+	obj = kubernetes.EditableData(obj)
+
+	yamlBytes, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(yamlBytes), nil
+}
+
+// fileName is suggested when saving for the first time
+func (a *App) openEditor(title, fileName, text string) {
+	var editor *ui.Editor
+	editor = ui.NewEditor(a.app, title, text,
+		func() { a.showEditorSavePrompt(editor, fileName) },
+		func() { a.applyFromEditor(editor) })
+	a.editor = editor
+
+	if a.validator != nil {
+		validator := a.validator
+		editor.SetValidator(func(ctx context.Context, text string) []validation.Diagnostic {
+			return validator.Validate(ctx, []byte(text))
+		})
+	}
+
+	a.pages.AddPage("editor", editor.CreateView(), true, true)
+	a.currentMode = modes.Editor
+}
+
+func (a *App) showEditorSavePrompt(editor *ui.Editor, fileName string) {
+	path := editor.Path()
+	if path == "" {
+		path = fileName
+	}
+
+	a.showInputPrompt("editor-save-prompt", " Save Manifest (Enter to save, Esc to cancel) ", "Save to: ", path, func(path string) {
+		if err := os.WriteFile(path, []byte(editor.Text()), 0o644); err != nil {
+			a.showErrorModal("Failed to save manifest", fmt.Sprintf("Error: %v", err))
+			return
+		}
+		editor.SetSaved(path)
+	})
+}
+
+// Preview and apply the edited manifest, coming back to the editor afterwards
+func (a *App) applyFromEditor(editor *ui.Editor) {
+	if a.kubeClient == nil {
+		editor.SetMessage("[red]not connected to a cluster[-]")
+		return
+	}
+
+	source := editor.Path()
+	if source == "" {
+		source = editor.Title()
+	}
+	a.openApplyMode(source, []byte(editor.Text()), func() {
+		a.pages.SwitchToPage("editor")
+		a.currentMode = modes.Editor
+		editor.Focus()
+	})
+}
+
+// Leave the editor, asking first if there are unsaved changes
+func (a *App) closeEditor() {
+	if !a.editor.Modified() {
+		a.leaveEditor()
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Discard unsaved changes to %s?", a.editor.Title())).
+		AddButtons([]string{"Discard", "Keep editing"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.pages.RemovePage("editor-discard")
+			if buttonLabel == "Discard" {
+				a.leaveEditor()
+			}
+		})
+	modal.SetBackgroundColor(tcell.ColorBlack)
+	modal.SetTextColor(tcell.ColorWhite)
+	a.pages.AddPage("editor-discard", modal, false, true)
+}
+
+func (a *App) leaveEditor() {
+	a.editor.Close()
+	a.editor = nil
+	a.pages.RemovePage("editor")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
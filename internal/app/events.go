@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Show all events of the current namespace, newest first
+func (a *App) showNamespaceEvents() {
+	if a.kubeClient == nil {
+		return
+	}
+
+	table := ui.NewListTable(fmt.Sprintf(" Events - Namespace: %s (Press 'R' to refresh, Esc to return) ", a.currentNamespace))
+	table.SetCell(0, 0, tview.NewTableCell("Loading events...").SetSelectable(false))
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'R' {
+			a.loadNamespaceEvents(table)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("events", table, true, true)
+	a.currentMode = modes.Events
+	a.loadNamespaceEvents(table)
+}
+
+func (a *App) loadNamespaceEvents(table *tview.Table) {
+	namespace := a.currentNamespace
+	go func() {
+		events, err := a.kubeClient.ListNamespaceEvents(context.Background(), namespace)
+		a.app.QueueUpdateDraw(func() {
+			ui.RenderEvents(table, events, err, true)
+		})
+	}()
+}
+
+func (a *App) closeNamespaceEvents() {
+	a.pages.RemovePage("events")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
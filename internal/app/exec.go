@@ -0,0 +1,253 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+	v1 "k8s.io/api/core/v1"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Start bash when the image has it, plain sh otherwise
+var execShellCommand = []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+// How often the local terminal is checked for size changes while a shell runs
+const terminalResizeInterval = 250 * time.Millisecond
+
+// Open a shell in the pod selected in the explorer, asking for the container
+// when more than one is running
+func (a *App) execIntoPod() {
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if a.kubeClient == nil || row == nil {
+		a.showErrorModal("No selection", "Please select a pod to open a shell in.")
+		return
+	}
+	if row.Kind != "Pod" {
+		a.showErrorModal("Unsupported resource", "Shells are only available for pods.")
+		return
+	}
+	selected := *row
+
+	go func() {
+		var pod v1.Pod
+		err := a.kubeClient.Get(context.Background(), selected.GVR, selected.Namespace, selected.Name, &pod)
+		if err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Failed to get pod", fmt.Sprintf("Error: %v", err))
+			})
+			return
+		}
+
+		containers := kubernetes.RunningContainers(&pod)
+		a.app.QueueUpdateDraw(func() {
+			switch len(containers) {
+			case 0:
+				a.showErrorModal("No running containers", fmt.Sprintf("Pod %s has no running containers to open a shell in.", pod.Name))
+			case 1:
+				a.runShell(selected, containers[0].Name)
+			default:
+				a.showExecContainerSelector(selected, &pod, containers)
+			}
+		})
+	}()
+}
+
+// Offer the containers with the one kubectl would pick listed first
+func (a *App) showExecContainerSelector(selected kubernetes.ResourceRow, pod *v1.Pod, containers []kubernetes.PodContainer) {
+	defaultContainer := kubernetes.DefaultContainer(pod)
+	if i := slices.IndexFunc(containers, func(c kubernetes.PodContainer) bool { return c.Name == defaultContainer }); i > 0 {
+		containers = append([]kubernetes.PodContainer{containers[i]}, slices.Delete(containers, i, i+1)...)
+	}
+
+	a.explorer.CreateContainerSelector(containers, a.pages, func(container string) {
+		// Let the selector switch back to the explorer before handing over the terminal
+		go a.app.QueueUpdateDraw(func() {
+			a.runShell(selected, container)
+		})
+	})
+}
+
+// Suspend the UI and attach the terminal to a shell in the container. Must run
+// on the UI goroutine, which stays blocked until the shell exits.
+func (a *App) runShell(selected kubernetes.ResourceRow, container string) {
+	var err error
+	a.app.Suspend(func() {
+		err = a.attachTerminal(selected.Namespace, selected.Name, container)
+	})
+
+	// Return to the pod the shell was opened from
+	a.explorer.SelectRow(a.explorerTable, func(row *kubernetes.ResourceRow) bool {
+		return row.UID == selected.UID && row.GVR == selected.GVR
+	})
+
+	if err != nil {
+		a.showErrorModal("Shell failed", fmt.Sprintf("Error: %v", err))
+	}
+}
+
+func (a *App) attachTerminal(namespace, pod, container string) error {
+	fmt.Printf("Connecting to %s/%s, container %s. Exit the shell to return.\r\n", namespace, pod, container)
+
+	tty, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	restore, err := tty.makeRaw()
+	if err != nil {
+		return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+	}
+	defer restore()
+
+	sizes := newTerminalSizeQueue(tty)
+	defer sizes.stop()
+
+	return a.kubeClient.Exec(context.Background(), namespace, pod, kubernetes.ExecOptions{
+		Container: container,
+		Command:   execShellCommand,
+		Stdin:     tty,
+		Stdout:    os.Stdout,
+		TTY:       true,
+		SizeQueue: sizes,
+	})
+}
+
+// Controlling terminal of the process. The executor keeps reading stdin after
+// the shell exits, so reads must be interruptible to not swallow input meant
+// for the resumed UI.
+type terminal struct {
+	in  *os.File // Read from and switched to raw mode
+	out *os.File // Measured for the terminal size
+	// in and out are the process's stdin and stdout, which stay open
+	stdio bool
+}
+
+// Open /dev/tty, or use stdin and stdout where there is none, e.g. on
+// Windows. Reads from stdin can't be interrupted, so there the first key
+// pressed after the shell exits may go to the finished exec.
+func openTerminal() (*terminal, error) {
+	file, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, fmt.Errorf("failed to open terminal: %w", err)
+		}
+		return &terminal{in: os.Stdin, out: os.Stdout, stdio: true}, nil
+	}
+	return &terminal{in: file, out: file}, nil
+}
+
+func (t *terminal) Read(p []byte) (int, error) {
+	return t.in.Read(p)
+}
+
+// Close unblocks pending reads, /dev/tty is pollable
+func (t *terminal) Close() error {
+	if t.stdio {
+		return nil
+	}
+	return t.in.Close()
+}
+
+// Run f with the file's descriptor without switching the file to blocking mode
+func control(file *os.File, f func(fd int)) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	return conn.Control(func(fd uintptr) {
+		f(int(fd))
+	})
+}
+
+func (t *terminal) makeRaw() (restore func(), err error) {
+	var state *term.State
+	controlErr := control(t.in, func(fd int) {
+		state, err = term.MakeRaw(fd)
+	})
+	if controlErr != nil {
+		return nil, controlErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		control(t.in, func(fd int) {
+			term.Restore(fd, state)
+		})
+	}, nil
+}
+
+func (t *terminal) size() (kubernetes.TerminalSize, bool) {
+	var width, height int
+	var err error
+	if controlErr := control(t.out, func(fd int) {
+		width, height, err = term.GetSize(fd)
+	}); controlErr != nil || err != nil {
+		return kubernetes.TerminalSize{}, false
+	}
+	return kubernetes.TerminalSize{Width: uint16(width), Height: uint16(height)}, true
+}
+
+// Reports the initial terminal size and every change to it. Polled rather
+// than driven by SIGWINCH so it works the same on every platform.
+type terminalSizeQueue struct {
+	sizes    chan kubernetes.TerminalSize
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newTerminalSizeQueue(tty *terminal) *terminalSizeQueue {
+	q := &terminalSizeQueue{
+		sizes: make(chan kubernetes.TerminalSize, 1),
+		done:  make(chan struct{}),
+	}
+
+	go func() {
+		ticker := time.NewTicker(terminalResizeInterval)
+		defer ticker.Stop()
+
+		var last kubernetes.TerminalSize
+		for {
+			if size, ok := tty.size(); ok && size != last {
+				last = size
+				select {
+				case q.sizes <- size:
+				case <-q.done:
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-q.done:
+				return
+			}
+		}
+	}()
+
+	return q
+}
+
+// Next blocks until the terminal size changes, returning nil once stopped
+func (q *terminalSizeQueue) Next() *kubernetes.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.done:
+		return nil
+	}
+}
+
+func (q *terminalSizeQueue) stop() {
+	q.stopOnce.Do(func() {
+		close(q.done)
+	})
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/ui"
+)
+
+// Result of listing one resource type for the explorer
+type resourceLoad struct {
+	resourceType string
+	gvr          schema.GroupVersionResource
+	namespace    string
+	table        *kubernetes.ResourceTable
+	err          error
+
+	refreshPending bool // A re-list is scheduled after watch events, only touched on the UI goroutine
+}
+
+// How long watch events are gathered before the changed resource type is
+// listed again. Bursts of updates, such as a rollout, cost one request.
+const explorerRefreshDelay = time.Second
+
+func (a *App) fetchResources(resourceType, namespace string) *resourceLoad {
+	load := &resourceLoad{resourceType: resourceType}
+
+	resourceInfo, err := a.kubeClient.ResolveResource(resourceType)
+	if err != nil {
+		load.err = err
+		return load
+	}
+
+	load.gvr = resourceInfo.GVR
+	if resourceInfo.Namespaced {
+		load.namespace = namespace
+	}
+
+	load.table, load.err = a.getResourcesInNamespace(resourceType, namespace)
+	return load
+}
+
+// Copy of the row under the cursor, taken before the loaded rows are modified
+func (a *App) selectedExplorerRow() *kubernetes.ResourceRow {
+	selected := a.explorer.SelectedRow(a.explorerTable)
+	if selected == nil {
+		return nil
+	}
+	row := *selected
+	return &row
+}
+
+// Re-render the explorer table from the loaded resources, keeping the cursor
+// on the previously selected object. Must run on the UI goroutine.
+func (a *App) renderExplorer(selected *kubernetes.ResourceRow) {
+	var sections []ui.ExplorerSection
+	for _, load := range a.explorerLoads {
+		section := ui.ExplorerSection{Title: load.resourceType, Table: load.table}
+		switch {
+		case load.err != nil:
+			section.Message = fmt.Sprintf("Error loading %s: %v", load.resourceType, load.err)
+		case len(load.table.Rows) == 0:
+			if a.currentResourceType != load.resourceType {
+				continue
+			}
+			section.Message = fmt.Sprintf("No %s found in this namespace", load.resourceType)
+		}
+		sections = append(sections, section)
+	}
+
+	a.explorer.RenderTable(a.explorerTable, sections)
+
+	if selected == nil {
+		a.explorer.SelectRow(a.explorerTable, nil)
+		return
+	}
+	a.explorer.SelectRow(a.explorerTable, func(row *kubernetes.ResourceRow) bool {
+		return row.UID == selected.UID && row.GVR == selected.GVR
+	})
+}
+
+// Replace the explorer contents with a single message. Must run on the UI goroutine.
+func (a *App) showExplorerMessage(message string) {
+	a.explorerLoads = nil
+	a.explorer.RenderTable(a.explorerTable, []ui.ExplorerSection{{Message: message}})
+}
+
+// Start informers for the loaded resource types. Their events are applied to
+// the explorer table until the next load bumps the generation.
+func (a *App) watchExplorerResources(generation int, loads []*resourceLoad) {
+	if a.informers == nil {
+		return
+	}
+
+	var stops []func()
+	for _, load := range loads {
+		if load.err != nil {
+			continue
+		}
+
+		stop, err := a.informers.Watch(load.gvr, load.namespace, func(event kubernetes.ResourceEvent) {
+			a.handleResourceEvent(generation, event)
+		})
+		if err != nil {
+			// Resources that cannot be watched simply stay static
+			continue
+		}
+		stops = append(stops, stop)
+	}
+
+	a.app.QueueUpdate(func() {
+		if generation != a.explorerGeneration {
+			for _, stop := range stops {
+				stop()
+			}
+			return
+		}
+		a.explorerWatches = stops
+	})
+}
+
+func (a *App) stopExplorerWatches() {
+	for _, stop := range a.explorerWatches {
+		stop()
+	}
+	a.explorerWatches = nil
+}
+
+// Called from informer goroutines for every change to a watched resource
+func (a *App) handleResourceEvent(generation int, event kubernetes.ResourceEvent) {
+	if event.Type == kubernetes.ResourceDeleted {
+		a.app.QueueUpdateDraw(func() {
+			a.removeExplorerRow(generation, event.GVR, event.Object.GetUID())
+		})
+		return
+	}
+
+	// Columns come from the server's table rendering, so changed objects are
+	// fetched again by listing their type once the events settle. The initial
+	// replay of objects the listing already rendered is skipped.
+	var load *resourceLoad
+	a.app.QueueUpdate(func() {
+		if a.explorerRowCurrent(generation, event.GVR, event.Object) {
+			return
+		}
+		load = a.explorerLoad(generation, event.GVR)
+		if load.refreshPending {
+			load = nil
+			return
+		}
+		load.refreshPending = true
+	})
+	if load != nil {
+		time.AfterFunc(explorerRefreshDelay, func() {
+			a.refreshExplorerLoad(generation, load)
+		})
+	}
+}
+
+// List a resource type again after watch events and re-render the explorer
+func (a *App) refreshExplorerLoad(generation int, load *resourceLoad) {
+	// Events from now on schedule another refresh, as this listing may miss them
+	var current bool
+	a.app.QueueUpdate(func() {
+		load.refreshPending = false
+		current = generation == a.explorerGeneration
+	})
+	if !current {
+		return
+	}
+
+	table, err := a.kubeClient.ListTable(context.Background(), load.gvr, load.namespace)
+	if err != nil {
+		// Keep the rows shown, the next event tries again
+		return
+	}
+
+	a.app.QueueUpdateDraw(func() {
+		if generation != a.explorerGeneration {
+			return
+		}
+		selected := a.selectedExplorerRow()
+		load.table = table
+		a.renderExplorer(selected)
+	})
+}
+
+// Whether the table already shows this version of the object, or the event
+// belongs to an earlier load and should be dropped
+func (a *App) explorerRowCurrent(generation int, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) bool {
+	load := a.explorerLoad(generation, gvr)
+	if load == nil {
+		return true
+	}
+
+	index := slices.IndexFunc(load.table.Rows, func(row kubernetes.ResourceRow) bool {
+		return row.UID == obj.GetUID()
+	})
+	return index >= 0 && load.table.Rows[index].ResourceVersion == obj.GetResourceVersion()
+}
+
+func (a *App) removeExplorerRow(generation int, gvr schema.GroupVersionResource, uid types.UID) {
+	load := a.explorerLoad(generation, gvr)
+	if load == nil {
+		return
+	}
+	selected := a.selectedExplorerRow()
+
+	load.table.Rows = slices.DeleteFunc(load.table.Rows, func(row kubernetes.ResourceRow) bool {
+		return row.UID == uid
+	})
+
+	a.renderExplorer(selected)
+}
+
+func (a *App) explorerLoad(generation int, gvr schema.GroupVersionResource) *resourceLoad {
+	if generation != a.explorerGeneration {
+		return nil
+	}
+	for _, load := range a.explorerLoads {
+		if load.err == nil && load.gvr == gvr {
+			return load
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Open the log viewer for the pod selected in the explorer
+func (a *App) showPodLogs() {
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if a.kubeClient == nil || row == nil {
+		a.showErrorModal("No selection", "Please select a pod to view logs.")
+		return
+	}
+	if row.Kind != "Pod" {
+		a.showErrorModal("Unsupported resource", "Logs are only available for pods.")
+		return
+	}
+	selected := *row
+
+	go func() {
+		var pod v1.Pod
+		err := a.kubeClient.Get(context.Background(), selected.GVR, selected.Namespace, selected.Name, &pod)
+		if err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Failed to get pod", fmt.Sprintf("Error: %v", err))
+			})
+			return
+		}
+
+		openStream := func(ctx context.Context, opts kubernetes.LogOptions) (io.ReadCloser, error) {
+			return a.kubeClient.StreamLogs(ctx, pod.Namespace, pod.Name, opts)
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			a.logView = ui.NewLogView(a.app, a.pages, pod.Name, kubernetes.PodContainers(&pod), kubernetes.DefaultContainer(&pod), openStream)
+			a.pages.AddPage("logs", a.logView.CreateView(), true, true)
+			a.currentMode = modes.Logs
+			a.logView.Start()
+		})
+	}()
+}
+
+func (a *App) closePodLogs() {
+	if a.logView != nil {
+		a.logView.Stop()
+		a.logView = nil
+	}
+	a.pages.RemovePage("logs")
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/modes"
+	"kubeguide/internal/ui"
+)
+
+// Ask which port of the selected pod or service to forward
+func (a *App) showPortForwardForm() {
+	row := a.explorer.SelectedRow(a.explorerTable)
+	if a.kubeClient == nil || row == nil {
+		a.showErrorModal("No selection", "Please select a pod or service to forward.")
+		return
+	}
+	if row.Kind != "Pod" && row.Kind != "Service" {
+		a.showErrorModal("Unsupported resource", "Port forwarding is only available for pods and services.")
+		return
+	}
+	selected := *row
+
+	go func() {
+		targets, err := a.portForwardTargets(selected)
+		if err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Port forward failed", fmt.Sprintf("Error: %v", err))
+			})
+			return
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			closeForm := func() {
+				a.pages.RemovePage("port-forward-form")
+			}
+			form := ui.NewPortForwardForm(targets, func(target kubernetes.PortForwardTarget, localPort int32) {
+				closeForm()
+				a.startPortForward(target, localPort)
+			}, closeForm)
+			a.pages.AddPage("port-forward-form", form, true, true)
+		})
+	}()
+}
+
+func (a *App) portForwardTargets(row kubernetes.ResourceRow) ([]kubernetes.PortForwardTarget, error) {
+	if row.Kind == "Service" {
+		return a.kubeClient.ServiceForwardTargets(context.Background(), row.Namespace, row.Name)
+	}
+
+	var pod v1.Pod
+	if err := a.kubeClient.Get(context.Background(), row.GVR, row.Namespace, row.Name, &pod); err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		return nil, fmt.Errorf("pod %s is %s, only running pods can be forwarded to", pod.Name, pod.Status.Phase)
+	}
+
+	targets := kubernetes.PodForwardTargets(&pod)
+	if len(targets) == 0 {
+		// Let the form ask for the port
+		targets = []kubernetes.PortForwardTarget{{Namespace: pod.Namespace, Pod: pod.Name, Source: "pod/" + pod.Name}}
+	}
+	return targets, nil
+}
+
+func (a *App) startPortForward(target kubernetes.PortForwardTarget, localPort int32) {
+	go func() {
+		forward, err := a.kubeClient.StartPortForward(target, localPort)
+		if err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showErrorModal("Port forward failed", fmt.Sprintf("Error: %v", err))
+			})
+			return
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			a.portForwards = append(a.portForwards, forward)
+			a.showPortForwards()
+		})
+
+		// Show forwards that end on their own, e.g. when the pod goes away
+		<-forward.Done()
+		a.app.QueueUpdateDraw(a.renderPortForwards)
+	}()
+}
+
+func (a *App) stopPortForward(forward *kubernetes.PortForward) {
+	a.portForwards = slices.DeleteFunc(a.portForwards, func(f *kubernetes.PortForward) bool {
+		return f == forward
+	})
+	a.renderPortForwards()
+
+	// Stop waits for the listener to close, keep it off the UI goroutine
+	go forward.Stop()
+}
+
+func (a *App) showPortForwards() {
+	if a.portForwardPanel == nil {
+		a.portForwardPanel = ui.NewPortForwardPanel(a.stopPortForward)
+		a.pages.AddPage("port-forwards", a.portForwardPanel.CreateView(), true, false)
+	}
+	a.renderPortForwards()
+	a.pages.SwitchToPage("port-forwards")
+	a.currentMode = modes.PortForwards
+}
+
+func (a *App) renderPortForwards() {
+	if a.portForwardPanel != nil {
+		a.portForwardPanel.Render(a.portForwards)
+	}
+}
+
+func (a *App) closePortForwards() {
+	a.pages.SwitchToPage("explorer")
+	a.currentMode = modes.Explorer
+}
+
+// Stop all forwards, waiting for their listeners to close
+func (a *App) stopPortForwards() {
+	for _, forward := range a.portForwards {
+		forward.Stop()
+	}
+	a.portForwards = nil
+}
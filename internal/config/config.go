@@ -1,12 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"kubeguide/internal/lint"
 )
 
 type AIConfig struct {
@@ -14,12 +17,40 @@ type AIConfig struct {
 	BaseURL  string `yaml:"base_url"`
 	Model    string `yaml:"model"`
 	APIKey   string `yaml:"api_key,omitempty"` // Optional in config, can use env var
+
+	// Diagnostic context sent along with a pod analysis
+	ContextTokens int `yaml:"context_tokens,omitempty"` // Budget for events, logs, owners etc., 6000 if unset
+	LogLines      int `yaml:"log_lines,omitempty"`      // Lines of current and previous logs per container, 50 if unset
+
+	Redaction RedactionConfig `yaml:"redaction,omitempty"`
+}
+
+// What is masked before anything is sent to the AI provider
+type RedactionConfig struct {
+	// Regular expressions for env var, annotation and ConfigMap key names
+	// whose values are masked, on top of the built-in ones like "passw" or "token"
+	SensitiveKeys []string `yaml:"sensitive_keys,omitempty"`
+	// Show what will be sent and ask before every request
+	ConfirmUpload bool `yaml:"confirm_upload,omitempty"`
+}
+
+// Extra lint rules, on top of the built-in ones
+type LintConfig struct {
+	Rules []lint.CELRuleSpec `yaml:"rules,omitempty"`
 }
 
 type Config struct {
-	AI AIConfig `yaml:"ai"`
+	AI   AIConfig   `yaml:"ai"`
+	Lint LintConfig `yaml:"lint,omitempty"`
+
+	// Lint.Rules compiled by Load
+	LintRules []lint.Rule `yaml:"-"`
 }
 
+// Returned by Load when lint rules don't compile. Unlike other problems with
+// the config file this stops kubeguide, a house rule must not go missing silently.
+var ErrInvalidLintRules = errors.New("invalid lint rules")
+
 func Load() (*Config, error) {
 	configPath, err := getConfigPath()
 	if err != nil {
@@ -39,6 +70,11 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	config.LintRules, err = lint.CompileCELRules(config.Lint.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %s:\n%w", ErrInvalidLintRules, configPath, err)
+	}
+
 	// Override with environment variables if available
 	if apiKey := os.Getenv("KUBEGUIDE_AI_API_KEY"); apiKey != "" {
 		config.AI.APIKey = apiKey
@@ -58,6 +94,20 @@ func Load() (*Config, error) {
 	return &config, nil
 }
 
+// Linter with the built-in rules and the config file's
+func (c *Config) Linter() *lint.Linter {
+	return lint.NewLinter(append(lint.BuiltinRules(), c.LintRules...)...)
+}
+
+// Linter with only the config file's rules, for manifest validation, where
+// the built-in best practices would bury schema problems. Nil without rules.
+func (c *Config) ValidationLinter() *lint.Linter {
+	if len(c.LintRules) == 0 {
+		return nil
+	}
+	return lint.NewLinter(c.LintRules...)
+}
+
 func getConfigPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -147,7 +197,8 @@ func detectProvider(aiConfig *AIConfig) string {
 	if strings.Contains(aiConfig.BaseURL, "openai.com") {
 		return "openai"
 	}
-	if strings.Contains(aiConfig.BaseURL, "localhost") || strings.Contains(aiConfig.BaseURL, "127.0.0.1") {
+	// Other local servers usually speak the OpenAI API, Ollama listens on 11434
+	if strings.Contains(aiConfig.BaseURL, ":11434") {
 		return "ollama"
 	}
 
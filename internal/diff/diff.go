@@ -0,0 +1,152 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+	Skip // Unchanged lines left out of a unified diff, Text describes them
+)
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Above this many line pairs the diff degrades to replacing the changed
+// middle section wholesale, to keep memory bounded for huge objects
+const maxTableSize = 4_000_000
+
+// Line diff turning a into b, based on their longest common subsequence
+func Lines(a, b string) []Line {
+	x, y := splitLines(a), splitLines(b)
+
+	// Common prefix and suffix are equal, only diff what lies between
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var lines []Line
+	for _, text := range x[:prefix] {
+		lines = append(lines, Line{Equal, text})
+	}
+	lines = append(lines, middle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, text := range x[len(x)-suffix:] {
+		lines = append(lines, Line{Equal, text})
+	}
+	return lines
+}
+
+func middle(x, y []string) []Line {
+	var lines []Line
+	if len(x)*len(y) > maxTableSize {
+		for _, text := range x {
+			lines = append(lines, Line{Delete, text})
+		}
+		for _, text := range y {
+			lines = append(lines, Line{Insert, text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	width := len(y) + 1
+	lcs := make([]int32, (len(x)+1)*width)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			lines = append(lines, Line{Delete, x[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		lines = append(lines, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		lines = append(lines, Line{Insert, y[j]})
+	}
+	return lines
+}
+
+// Keep only changes and the given number of unchanged lines around them,
+// replacing longer unchanged runs with a Skip line
+func Unified(lines []Line, context int) []Line {
+	var result []Line
+	for start := 0; start < len(lines); {
+		if lines[start].Op != Equal {
+			result = append(result, lines[start])
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(lines) && lines[end].Op == Equal {
+			end++
+		}
+
+		// Context after the previous change and before the next one
+		keepHead, keepTail := context, context
+		if start == 0 {
+			keepHead = 0
+		}
+		if end == len(lines) {
+			keepTail = 0
+		}
+
+		if end-start <= keepHead+keepTail {
+			result = append(result, lines[start:end]...)
+		} else {
+			result = append(result, lines[start:start+keepHead]...)
+			result = append(result, Line{Skip, fmt.Sprintf("%d unchanged lines", end-start-keepHead-keepTail)})
+			result = append(result, lines[end-keepTail:end]...)
+		}
+		start = end
+	}
+	return result
+}
+
+// Whether any line differs
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op == Insert || line.Op == Delete {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
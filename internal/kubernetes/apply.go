@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Field manager recorded in managedFields for changes applied from kubeguide
+const DefaultFieldManager = "kubeguide"
+
+type ApplyOptions struct {
+	FieldManager string // Defaults to DefaultFieldManager
+	Force        bool   // Take ownership of fields other managers own
+	DryRun       bool   // Let the server compute the result without persisting it
+}
+
+// Field another manager owns that the applied object sets to a different value
+type ApplyConflict struct {
+	Manager string // e.g. "kubectl-client-side-apply" or "helm"
+	Field   string // Field path, e.g. ".spec.replicas"
+	Message string // The server's description, which includes the operation and API version
+}
+
+// Apply was refused because of field ownership conflicts. Forcing the apply
+// transfers ownership of the listed fields.
+type ApplyConflictError struct {
+	Conflicts []ApplyConflict
+	Err       error
+}
+
+func (e *ApplyConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ApplyConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Server-side apply obj, returning the object as the server stored it, or
+// would store it for a dry run. Conflicts are returned as *ApplyConflictError.
+func (c *UnifiedClient) Apply(ctx context.Context, gvr schema.GroupVersionResource, namespace string, obj *unstructured.Unstructured, opts ApplyOptions) (*unstructured.Unstructured, error) {
+	resourceInfo, err := c.writableResourceInfo(gvr, namespace, "patch")
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("cannot apply %v without a name", gvr)
+	}
+
+	// Apply configurations must not carry managedFields
+	applied := obj.DeepCopy()
+	applied.SetManagedFields(nil)
+	if applied.GetKind() == "" {
+		applied.SetGroupVersionKind(resourceInfo.GVK)
+	}
+
+	data, err := json.Marshal(applied.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+	}
+
+	patchOptions := metav1.PatchOptions{
+		FieldManager: opts.FieldManager,
+		Force:        &opts.Force,
+	}
+	if patchOptions.FieldManager == "" {
+		patchOptions.FieldManager = DefaultFieldManager
+	}
+	if opts.DryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	result, err := c.getResourceInterface(gvr, namespace).Patch(ctx, obj.GetName(), types.ApplyPatchType, data, patchOptions)
+	if err != nil {
+		err = newAPIError("apply", gvr, namespace, obj.GetName(), err)
+		if conflicts := applyConflicts(err); len(conflicts) > 0 {
+			return nil, &ApplyConflictError{Conflicts: conflicts, Err: err}
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Conflicts listed in the causes of an apply conflict error
+func applyConflicts(err error) []ApplyConflict {
+	var status apierrors.APIStatus
+	if !IsConflict(err) || !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+
+	var conflicts []ApplyConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, ApplyConflict{
+			Manager: conflictManager(cause.Message),
+			Field:   cause.Field,
+			Message: cause.Message,
+		})
+	}
+	return conflicts
+}
+
+// Manager name from a cause message like `conflict with "helm" using apps/v1`
+func conflictManager(message string) string {
+	rest, found := strings.CutPrefix(message, "conflict with ")
+	if !found {
+		return message
+	}
+	quoted, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return rest
+	}
+	manager, err := strconv.Unquote(quoted)
+	if err != nil {
+		return rest
+	}
+	return manager
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,18 +16,23 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 type ResourceInfo struct {
-	GVR        schema.GroupVersionResource
-	GVK        schema.GroupVersionKind
-	Namespaced bool
-	IsCustom   bool
+	GVR          schema.GroupVersionResource
+	GVK          schema.GroupVersionKind
+	Namespaced   bool
+	IsCustom     bool
+	Preferred    bool // GVR uses the group's preferred version
+	SingularName string
+	ShortNames   []string
+	Verbs        []string
+	Categories   []string
 }
 
 type UnifiedClient struct {
@@ -39,15 +47,18 @@ type UnifiedClient struct {
 	cacheMutex    sync.RWMutex
 	lastDiscovery time.Time
 	cacheTimeout  time.Duration
+	failedGroups  map[schema.GroupVersion]error
+
+	// Kubeconfig context the client was built from
+	options          ClientOptions
+	contextName      string
+	defaultNamespace string
 }
 
-func NewUnifiedClient() (*UnifiedClient, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+func NewUnifiedClient(opts ClientOptions) (*UnifiedClient, error) {
+	config, contextName, namespace, err := loadRestConfig(opts)
 	if err != nil {
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	typedClient, err := kubernetes.NewForConfig(config)
@@ -71,13 +82,17 @@ func NewUnifiedClient() (*UnifiedClient, error) {
 	}
 
 	client := &UnifiedClient{
-		typedClient:     typedClient,
-		dynamicClient:   dynamicClient,
-		discoveryClient: discoveryClient,
-		crdClient:       crdClient,
-		config:          config,
-		resourceCache:   make(map[schema.GroupVersionResource]*ResourceInfo),
-		cacheTimeout:    5 * time.Minute, // Cache for 5 minutes
+		typedClient:      typedClient,
+		dynamicClient:    dynamicClient,
+		discoveryClient:  discoveryClient,
+		crdClient:        crdClient,
+		config:           config,
+		resourceCache:    make(map[schema.GroupVersionResource]*ResourceInfo),
+		failedGroups:     make(map[schema.GroupVersion]error),
+		cacheTimeout:     5 * time.Minute, // Cache for 5 minutes
+		options:          opts,
+		contextName:      contextName,
+		defaultNamespace: namespace,
 	}
 
 	// Initial discovery
@@ -88,6 +103,31 @@ func NewUnifiedClient() (*UnifiedClient, error) {
 	return client, nil
 }
 
+// Name of the kubeconfig context in use
+func (c *UnifiedClient) CurrentContext() string {
+	return c.contextName
+}
+
+// Default namespace of the current context, "default" if it sets none
+func (c *UnifiedClient) DefaultNamespace() string {
+	return c.defaultNamespace
+}
+
+// Options the client was created with
+func (c *UnifiedClient) Options() ClientOptions {
+	return c.options
+}
+
+// Discovery client, e.g. for the cluster's OpenAPI schemas
+func (c *UnifiedClient) DiscoveryClient() discovery.DiscoveryInterface {
+	return c.discoveryClient
+}
+
+// Client for CustomResourceDefinitions
+func (c *UnifiedClient) CRDClient() apiextclient.Interface {
+	return c.crdClient
+}
+
 // Discover all available resources (core + custom)
 func (c *UnifiedClient) discoverResources() error {
 	c.cacheMutex.Lock()
@@ -95,56 +135,72 @@ func (c *UnifiedClient) discoverResources() error {
 
 	// Clear existing cache
 	c.resourceCache = make(map[schema.GroupVersionResource]*ResourceInfo)
+	c.failedGroups = make(map[schema.GroupVersion]error)
 
-	// Discover core resources
-	if err := c.discoverCoreResources(); err != nil {
-		return fmt.Errorf("failed to discover core resources: %w", err)
+	// Discover everything the API server serves
+	if err := c.discoverServedResources(); err != nil {
+		return fmt.Errorf("failed to discover served resources: %w", err)
 	}
 
-	// Discover custom resources
+	// Mark custom resources from CRDs. Served CRDs are already known from
+	// discovery, so a failure here (e.g. no RBAC to list CRDs) is not fatal
 	if err := c.discoverCustomResources(); err != nil {
-		return fmt.Errorf("failed to discover custom resources: %w", err)
+		c.failedGroups[apiextv1.SchemeGroupVersion] = err
 	}
 
 	c.lastDiscovery = time.Now()
 	return nil
 }
 
-// Discover core Kubernetes resources
-func (c *UnifiedClient) discoverCoreResources() error {
-	coreResources := []ResourceInfo{
-		{GVR: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
-			GVK:        schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"},
-			GVK:        schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
-			GVK:        schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"},
-			GVK:        schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"},
-			GVK:        schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"},
-			Namespaced: false, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
-			GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"},
-			GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"},
-			GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
-			Namespaced: true, IsCustom: false},
-		{GVR: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
-			GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
-			Namespaced: true, IsCustom: false},
-	}
-
-	for _, resource := range coreResources {
-		resourceCopy := resource
-		c.resourceCache[resource.GVR] = &resourceCopy
+// Discover every resource served by the API server, including aggregated APIs
+func (c *UnifiedClient) discoverServedResources() error {
+	groups, resourceLists, err := c.discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		// A broken aggregated API (e.g. an unavailable metrics-server) only
+		// fails its own group, so keep whatever else was discovered
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return err
+		}
+		for gv, groupErr := range err.(*discovery.ErrGroupDiscoveryFailed).Groups {
+			c.failedGroups[gv] = groupErr
+		}
+	}
+
+	preferredVersions := make(map[string]string)
+	for _, group := range groups {
+		preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, apiResource := range list.APIResources {
+			// Skip subresources such as pods/log or deployments/scale
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+
+			gvk := gv.WithKind(apiResource.Kind)
+			if apiResource.Group != "" || apiResource.Version != "" {
+				gvk = schema.GroupVersionKind{Group: apiResource.Group, Version: apiResource.Version, Kind: apiResource.Kind}
+			}
+
+			gvr := gv.WithResource(apiResource.Name)
+			c.resourceCache[gvr] = &ResourceInfo{
+				GVR:          gvr,
+				GVK:          gvk,
+				Namespaced:   apiResource.Namespaced,
+				IsCustom:     false,
+				Preferred:    preferredVersions[gv.Group] == gv.Version,
+				SingularName: apiResource.SingularName,
+				ShortNames:   apiResource.ShortNames,
+				Verbs:        apiResource.Verbs,
+				Categories:   apiResource.Categories,
+			}
+		}
 	}
 
 	return nil
@@ -179,10 +235,20 @@ func (c *UnifiedClient) discoverCustomResources() error {
 			}
 
 			resourceInfo := &ResourceInfo{
-				GVR:        gvr,
-				GVK:        gvk,
-				Namespaced: crd.Spec.Scope == apiextv1.NamespaceScoped,
-				IsCustom:   true,
+				GVR:          gvr,
+				GVK:          gvk,
+				Namespaced:   crd.Spec.Scope == apiextv1.NamespaceScoped,
+				IsCustom:     true,
+				Preferred:    version.Storage,
+				SingularName: crd.Spec.Names.Singular,
+				ShortNames:   crd.Spec.Names.ShortNames,
+				Categories:   crd.Spec.Names.Categories,
+			}
+
+			// Keep verbs and preferred version from discovery when available
+			if discovered, ok := c.resourceCache[gvr]; ok {
+				resourceInfo.Verbs = discovered.Verbs
+				resourceInfo.Preferred = discovered.Preferred
 			}
 
 			c.resourceCache[gvr] = resourceInfo
@@ -256,22 +322,89 @@ func (c *UnifiedClient) List(ctx context.Context, gvr schema.GroupVersionResourc
 	return c.listDynamicResource(ctx, gvr, namespace, obj)
 }
 
-// func (c *UnifiedClient) Create(ctx context.Context, gvr schema.GroupVersionResource, namespace string, obj any) error {
-// 	resourceInfo, err := c.getResourceInfo(gvr)
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	// Validate namespace usage
-// 	if namespace != "" && !resourceInfo.Namespaced {
-// 		return fmt.Errorf("resource %v is cluster-scoped, cannot specify namespace", gvr)
-// 	}
-//
-// 	if !resourceInfo.IsCustom {
-// 		return c.createTypedResource(ctx, gvr, namespace, obj)
-// 	}
-// 	return c.createDynamicResource(ctx, gvr, namespace, obj)
-// }
+// Options for Delete, the zero value uses the resource's defaults
+type DeleteOptions struct {
+	PropagationPolicy  metav1.DeletionPropagation // Background, Foreground or Orphan
+	GracePeriodSeconds *int64                     // 0 deletes immediately
+}
+
+// Writes go through the dynamic client for every resource, typed objects are
+// converted to unstructured and back. obj receives the server's response.
+func (c *UnifiedClient) Create(ctx context.Context, gvr schema.GroupVersionResource, namespace string, obj any) error {
+	resourceInfo, err := c.writableResourceInfo(gvr, namespace, "create")
+	if err != nil {
+		return err
+	}
+	return c.createDynamicResource(ctx, resourceInfo, namespace, obj)
+}
+
+// Replace an existing object. The object's resourceVersion guards against
+// overwriting concurrent changes, a mismatch fails with a conflict.
+func (c *UnifiedClient) Update(ctx context.Context, gvr schema.GroupVersionResource, namespace string, obj any) error {
+	resourceInfo, err := c.writableResourceInfo(gvr, namespace, "update")
+	if err != nil {
+		return err
+	}
+	return c.updateDynamicResource(ctx, resourceInfo, namespace, obj)
+}
+
+// Patch an object with a JSON, merge or strategic merge patch. obj may be nil
+// when the patched object is not needed.
+func (c *UnifiedClient) Patch(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, patchType types.PatchType, data []byte, obj any) error {
+	resourceInfo, err := c.writableResourceInfo(gvr, namespace, "patch")
+	if err != nil {
+		return err
+	}
+
+	switch patchType {
+	case types.JSONPatchType, types.MergePatchType:
+	case types.StrategicMergePatchType:
+		// Strategic merge needs the Go types' patch strategies, which CRDs lack
+		if resourceInfo.IsCustom {
+			return fmt.Errorf("strategic merge patch is not supported for custom resource %v, use a merge patch", gvr)
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %q", patchType)
+	}
+
+	return c.patchDynamicResource(ctx, gvr, namespace, name, patchType, data, obj)
+}
+
+func (c *UnifiedClient) Delete(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, opts DeleteOptions) error {
+	if _, err := c.writableResourceInfo(gvr, namespace, "delete"); err != nil {
+		return err
+	}
+
+	deleteOptions := metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	if opts.PropagationPolicy != "" {
+		deleteOptions.PropagationPolicy = &opts.PropagationPolicy
+	}
+
+	err := c.getResourceInterface(gvr, namespace).Delete(ctx, name, deleteOptions)
+	return newAPIError("delete", gvr, namespace, name, err)
+}
+
+// Resource info for a write, checking scope and that the verb is served
+func (c *UnifiedClient) writableResourceInfo(gvr schema.GroupVersionResource, namespace, verb string) (*ResourceInfo, error) {
+	resourceInfo, err := c.getResourceInfo(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate namespace usage
+	if namespace != "" && !resourceInfo.Namespaced {
+		return nil, fmt.Errorf("resource %v is cluster-scoped, cannot specify namespace", gvr)
+	}
+	if namespace == "" && resourceInfo.Namespaced {
+		return nil, fmt.Errorf("resource %v is namespaced, a namespace is required", gvr)
+	}
+
+	if !resourceInfo.HasVerb(verb) {
+		return nil, fmt.Errorf("resource %v does not support %s", gvr, verb)
+	}
+
+	return resourceInfo, nil
+}
 
 // List all available resources in the cluster
 func (c *UnifiedClient) ListAvailableResources() ([]ResourceInfo, error) {
@@ -307,6 +440,125 @@ func (c *UnifiedClient) ListCustomResources() ([]ResourceInfo, error) {
 	return customResources, nil
 }
 
+// List resources that can be browsed: listable, at their group's preferred
+// version, sorted by group then resource name
+func (c *UnifiedClient) ListBrowsableResources() ([]ResourceInfo, error) {
+	allResources, err := c.ListAvailableResources()
+	if err != nil {
+		return nil, err
+	}
+
+	var browsable []ResourceInfo
+	for _, resource := range allResources {
+		if resource.Preferred && resource.HasVerb("list") {
+			browsable = append(browsable, resource)
+		}
+	}
+
+	sort.Slice(browsable, func(i, j int) bool {
+		if browsable[i].GVR.Group != browsable[j].GVR.Group {
+			return browsable[i].GVR.Group < browsable[j].GVR.Group
+		}
+		return browsable[i].GVR.Resource < browsable[j].GVR.Resource
+	})
+
+	return browsable, nil
+}
+
+// Resolve a resource from its kind, plural, singular or short name, optionally
+// qualified with its group (e.g. "deployments.apps" or "Ingress.networking.k8s.io").
+// Ambiguous names resolve the same way kubectl prefers them: preferred versions,
+// then the core group, then built-in over custom resources.
+func (c *UnifiedClient) ResolveResource(name string) (*ResourceInfo, error) {
+	if err := c.ensureFreshCache(); err != nil {
+		return nil, err
+	}
+
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+
+	resourceName, group, qualified := strings.Cut(strings.ToLower(name), ".")
+
+	var best *ResourceInfo
+	for _, info := range c.resourceCache {
+		if qualified && strings.ToLower(info.GVR.Group) != group {
+			continue
+		}
+		if !info.matchesName(resourceName) {
+			continue
+		}
+		if best == nil || info.resolvesBefore(best) {
+			best = info
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("resource type %q not found in cluster", name)
+	}
+
+	resolved := *best
+	return &resolved, nil
+}
+
+// Resource serving a kind, as needed to act on objects read from manifests
+func (c *UnifiedClient) ResourceForGVK(gvk schema.GroupVersionKind) (*ResourceInfo, error) {
+	if err := c.ensureFreshCache(); err != nil {
+		return nil, err
+	}
+
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+
+	// Several resources can serve a kind, pick one regardless of map order
+	var best *ResourceInfo
+	for _, info := range c.resourceCache {
+		if info.GVK == gvk && (best == nil || info.resolvesBefore(best)) {
+			best = info
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no resource for kind %s in %s found in cluster", gvk.Kind, gvk.GroupVersion())
+	}
+
+	resolved := *best
+	return &resolved, nil
+}
+
+// Check whether the resource supports an API verb such as "list" or "watch"
+func (r ResourceInfo) HasVerb(verb string) bool {
+	// CRDs discovered without API discovery have no verbs, assume full support
+	if len(r.Verbs) == 0 {
+		return true
+	}
+	return slices.Contains(r.Verbs, verb)
+}
+
+func (r *ResourceInfo) matchesName(name string) bool {
+	if name == r.GVR.Resource || name == strings.ToLower(r.GVK.Kind) || name == r.SingularName {
+		return true
+	}
+	return slices.Contains(r.ShortNames, name)
+}
+
+func (r *ResourceInfo) resolvesBefore(other *ResourceInfo) bool {
+	if r.Preferred != other.Preferred {
+		return r.Preferred
+	}
+	if (r.GVR.Group == "") != (other.GVR.Group == "") {
+		return r.GVR.Group == ""
+	}
+	if r.IsCustom != other.IsCustom {
+		return !r.IsCustom
+	}
+	if r.GVR.Group != other.GVR.Group {
+		return r.GVR.Group < other.GVR.Group
+	}
+	if r.GVR.Version != other.GVR.Version {
+		return r.GVR.Version < other.GVR.Version
+	}
+	return r.GVR.Resource < other.GVR.Resource
+}
+
 // Check if a resource exists in the cluster
 func (c *UnifiedClient) ResourceExists(gvr schema.GroupVersionResource) bool {
 	_, err := c.getResourceInfo(gvr)
@@ -322,6 +574,18 @@ func (c *UnifiedClient) GetGVK(gvr schema.GroupVersionResource) (schema.GroupVer
 	return resourceInfo.GVK, nil
 }
 
+// Groups whose discovery failed during the last refresh, keyed by group version
+func (c *UnifiedClient) FailedDiscoveryGroups() map[schema.GroupVersion]error {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+
+	failed := make(map[schema.GroupVersion]error, len(c.failedGroups))
+	for gv, err := range c.failedGroups {
+		failed[gv] = err
+	}
+	return failed
+}
+
 // Force refresh of resource cache
 func (c *UnifiedClient) RefreshResourceCache() error {
 	return c.discoverResources()
@@ -357,7 +621,8 @@ func (c *UnifiedClient) getTypedResource(ctx context.Context, gvr schema.GroupVe
 		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(*deploy))
 
 	default:
-		return fmt.Errorf("unsupported core resource: %v", gvr)
+		// Any other discovered resource is fetched dynamically and converted
+		return c.getDynamicResource(ctx, gvr, namespace, name, obj)
 	}
 
 	return nil
@@ -392,7 +657,8 @@ func (c *UnifiedClient) listTypedResource(ctx context.Context, gvr schema.GroupV
 		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(*namespaces))
 
 	default:
-		return fmt.Errorf("unsupported core resource: %v", gvr)
+		// Any other discovered resource is listed dynamically and converted
+		return c.listDynamicResource(ctx, gvr, namespace, obj)
 	}
 
 	return nil
@@ -435,25 +701,75 @@ func (c *UnifiedClient) listDynamicResource(ctx context.Context, gvr schema.Grou
 	return nil
 }
 
-func (c *UnifiedClient) createDynamicResource(ctx context.Context, gvr schema.GroupVersionResource, namespace string, obj any) error {
-	resourceInterface := c.getResourceInterface(gvr, namespace)
+func (c *UnifiedClient) createDynamicResource(ctx context.Context, resourceInfo *ResourceInfo, namespace string, obj any) error {
+	gvr := resourceInfo.GVR
+	unstructuredObj, err := toUnstructured(obj, resourceInfo.GVK)
+	if err != nil {
+		return err
+	}
 
-	var unstructuredObj *unstructured.Unstructured
-	var err error
+	created, err := c.getResourceInterface(gvr, namespace).Create(ctx, unstructuredObj, metav1.CreateOptions{})
+	if err != nil {
+		return newAPIError("create", gvr, namespace, unstructuredObj.GetName(), err)
+	}
+	return fromUnstructured(created, obj)
+}
 
-	if u, ok := obj.(*unstructured.Unstructured); ok {
-		unstructuredObj = u
-	} else {
+func (c *UnifiedClient) updateDynamicResource(ctx context.Context, resourceInfo *ResourceInfo, namespace string, obj any) error {
+	gvr := resourceInfo.GVR
+	unstructuredObj, err := toUnstructured(obj, resourceInfo.GVK)
+	if err != nil {
+		return err
+	}
+	if unstructuredObj.GetName() == "" {
+		return fmt.Errorf("cannot update %v without a name", gvr)
+	}
+
+	updated, err := c.getResourceInterface(gvr, namespace).Update(ctx, unstructuredObj, metav1.UpdateOptions{})
+	if err != nil {
+		return newAPIError("update", gvr, namespace, unstructuredObj.GetName(), err)
+	}
+	return fromUnstructured(updated, obj)
+}
+
+func (c *UnifiedClient) patchDynamicResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, patchType types.PatchType, data []byte, obj any) error {
+	patched, err := c.getResourceInterface(gvr, namespace).Patch(ctx, name, patchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return newAPIError("patch", gvr, namespace, name, err)
+	}
+	if obj == nil {
+		return nil
+	}
+	return fromUnstructured(patched, obj)
+}
+
+// Unstructured form of obj, which is used as is when already unstructured.
+// Typed objects from the typed clients have no apiVersion and kind, those
+// default to the resource's GVK.
+func toUnstructured(obj any, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
 		// Convert typed object to unstructured
 		unstructuredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		unstructuredObj = &unstructured.Unstructured{Object: unstructuredMap}
+		u = &unstructured.Unstructured{Object: unstructuredMap}
 	}
 
-	_, err = resourceInterface.Create(ctx, unstructuredObj, metav1.CreateOptions{})
-	return err
+	if u.GetKind() == "" {
+		u.SetGroupVersionKind(gvk)
+	}
+	return u, nil
+}
+
+// Store a server response in obj, converting it for typed targets
+func fromUnstructured(result *unstructured.Unstructured, obj any) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		u.Object = result.Object
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(result.Object, obj)
 }
 
 func (c *UnifiedClient) getResourceInterface(gvr schema.GroupVersionResource, namespace string) dynamic.ResourceInterface {
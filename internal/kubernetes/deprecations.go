@@ -0,0 +1,298 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// An API version of a kind that Kubernetes deprecated, and possibly removed
+type DeprecatedAPI struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string // Kubernetes version, e.g. "v1.21"
+	RemovedIn    string
+	Replacement  string // API version to migrate to, empty if the kind is gone
+	Note         string // What else changes when migrating
+}
+
+// Deprecated API versions of kinds kept in manifests, from the Kubernetes
+// deprecated API migration guide. Review objects like TokenReview and
+// transient ones like Event are left out.
+var DeprecatedAPIs = []DeprecatedAPI{
+	// Removed in v1.16
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "v1.11", RemovedIn: "v1.16", Replacement: "policy/v1beta1"},
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1", Note: "spec.selector is required and immutable, spec.updateStrategy defaults to RollingUpdate"},
+	{APIVersion: "apps/v1beta2", Kind: "DaemonSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1", Note: "spec.selector is required and immutable, spec.rollbackTo is removed"},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1", Note: "spec.selector is required and immutable"},
+	{APIVersion: "apps/v1beta2", Kind: "Deployment", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta1", Kind: "StatefulSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1", Note: "spec.selector is required and immutable, spec.updateStrategy defaults to RollingUpdate"},
+	{APIVersion: "apps/v1beta2", Kind: "StatefulSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1", Note: "spec.selector is required and immutable"},
+	{APIVersion: "apps/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", DeprecatedIn: "v1.9", RemovedIn: "v1.16", Replacement: "apps/v1"},
+
+	// Removed in v1.22
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", DeprecatedIn: "v1.16", RemovedIn: "v1.22", Replacement: "admissionregistration.k8s.io/v1", Note: "webhooks[*].admissionReviewVersions and sideEffects are required"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", DeprecatedIn: "v1.16", RemovedIn: "v1.22", Replacement: "admissionregistration.k8s.io/v1", Note: "webhooks[*].admissionReviewVersions and sideEffects are required"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "v1.16", RemovedIn: "v1.22", Replacement: "apiextensions.k8s.io/v1", Note: "spec.versions[*].schema.openAPIV3Schema is required and must be structural"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "apiregistration.k8s.io/v1"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "certificates.k8s.io/v1", Note: "spec.signerName is required"},
+	{APIVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "coordination.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", DeprecatedIn: "v1.14", RemovedIn: "v1.22", Replacement: "networking.k8s.io/v1", Note: "backend fields are renamed to service.name and service.port, spec.rules[*].http.paths[*].pathType is required"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "networking.k8s.io/v1", Note: "backend fields are renamed to service.name and service.port, spec.rules[*].http.paths[*].pathType is required"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", DeprecatedIn: "v1.17", RemovedIn: "v1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "v1.17", RemovedIn: "v1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", DeprecatedIn: "v1.17", RemovedIn: "v1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", DeprecatedIn: "v1.17", RemovedIn: "v1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", DeprecatedIn: "v1.14", RemovedIn: "v1.22", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIDriver", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSINode", DeprecatedIn: "v1.17", RemovedIn: "v1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "VolumeAttachment", DeprecatedIn: "v1.19", RemovedIn: "v1.22", Replacement: "storage.k8s.io/v1"},
+
+	// Removed in v1.25
+	{APIVersion: "batch/v1beta1", Kind: "CronJob", DeprecatedIn: "v1.21", RemovedIn: "v1.25", Replacement: "batch/v1"},
+	{APIVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", DeprecatedIn: "v1.21", RemovedIn: "v1.25", Replacement: "discovery.k8s.io/v1", Note: "endpoints[*].topology is replaced by nodeName and zone"},
+	{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "v1.22", RemovedIn: "v1.25", Replacement: "autoscaling/v2", Note: "metric targets move to target.averageValue/averageUtilization"},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "v1.21", RemovedIn: "v1.25", Replacement: "policy/v1", Note: "an empty spec.selector selects every pod in the namespace"},
+	{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "v1.21", RemovedIn: "v1.25", Note: "no replacement, use Pod Security Admission or a policy engine"},
+	{APIVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", DeprecatedIn: "v1.20", RemovedIn: "v1.25", Replacement: "node.k8s.io/v1"},
+
+	// Removed in v1.26
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "FlowSchema", DeprecatedIn: "v1.23", RemovedIn: "v1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "PriorityLevelConfiguration", DeprecatedIn: "v1.23", RemovedIn: "v1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "v1.23", RemovedIn: "v1.26", Replacement: "autoscaling/v2"},
+
+	// Removed in v1.27
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "v1.24", RemovedIn: "v1.27", Replacement: "storage.k8s.io/v1"},
+
+	// Removed in v1.29
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "FlowSchema", DeprecatedIn: "v1.26", RemovedIn: "v1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "PriorityLevelConfiguration", DeprecatedIn: "v1.26", RemovedIn: "v1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+
+	// Removed in v1.32
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", DeprecatedIn: "v1.29", RemovedIn: "v1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "PriorityLevelConfiguration", DeprecatedIn: "v1.29", RemovedIn: "v1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1", Note: "spec.limited.nominalConcurrencyShares 0 now means 0 instead of 30"},
+}
+
+// Deprecation of an API version of a kind, nil if it isn't deprecated
+func LookupDeprecatedAPI(apiVersion, kind string) *DeprecatedAPI {
+	for i := range DeprecatedAPIs {
+		if DeprecatedAPIs[i].APIVersion == apiVersion && DeprecatedAPIs[i].Kind == kind {
+			return &DeprecatedAPIs[i]
+		}
+	}
+	return nil
+}
+
+// Newest Kubernetes version the table removes APIs in
+func NewestRemovalVersion() string {
+	newest := ""
+	for _, api := range DeprecatedAPIs {
+		if newest == "" || CompareKubeVersions(api.RemovedIn, newest) > 0 {
+			newest = api.RemovedIn
+		}
+	}
+	return newest
+}
+
+// Use of a deprecated API version by a manifest or live object
+type DeprecationFinding struct {
+	API     DeprecatedAPI
+	Object  string // Kind and namespaced name, e.g. "Deployment default/web"
+	Source  string // Where the API version was seen: a file and line, or how a live object was written
+	Removed bool   // Whether the API version is gone in the target version, deprecated otherwise
+}
+
+// Uses of API versions deprecated by target in a YAML or JSON manifest, source
+// names the manifest in the findings
+func ScanManifestDeprecations(source string, data []byte, target string) ([]DeprecationFinding, error) {
+	var findings []DeprecationFinding
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return findings, fmt.Errorf("%s: %w", source, err)
+		}
+		if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		var obj map[string]any
+		if err := document.Decode(&obj); err != nil {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		objects := []*unstructured.Unstructured{u}
+		if u.IsList() {
+			// Items of a List have no lines of their own here, the List's line stands for them
+			objects = nil
+			if list, err := u.ToList(); err == nil {
+				for i := range list.Items {
+					objects = append(objects, &list.Items[i])
+				}
+			}
+		}
+
+		line := fmt.Sprintf("%s:%d", source, document.Content[0].Line)
+		for _, item := range objects {
+			if finding := deprecationFinding(item.GetAPIVersion(), item, line, target); finding != nil {
+				findings = append(findings, *finding)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// Uses of API versions deprecated by target in how a live object was written:
+// its last-applied-configuration from kubectl apply and the API versions its
+// field managers used. The server converts stored objects, so these are the
+// only traces of the API versions manifests and controllers still use.
+func ScanObjectDeprecations(obj *unstructured.Unstructured, target string) []DeprecationFinding {
+	var findings []DeprecationFinding
+	seen := make(map[string]bool)
+	add := func(apiVersion, source string) {
+		if seen[apiVersion+"\x00"+source] {
+			return
+		}
+		seen[apiVersion+"\x00"+source] = true
+		if finding := deprecationFinding(apiVersion, obj, source, target); finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+
+	if lastApplied := obj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; lastApplied != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil && applied.APIVersion != "" {
+			add(applied.APIVersion, "last-applied-configuration")
+		}
+	}
+	for _, entry := range obj.GetManagedFields() {
+		add(entry.APIVersion, fmt.Sprintf("managedFields (%s, %s)", entry.Manager, entry.Operation))
+	}
+	return findings
+}
+
+func deprecationFinding(apiVersion string, obj *unstructured.Unstructured, source, target string) *DeprecationFinding {
+	api := LookupDeprecatedAPI(apiVersion, obj.GetKind())
+	if api == nil || CompareKubeVersions(api.DeprecatedIn, target) > 0 {
+		return nil
+	}
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	return &DeprecationFinding{
+		API:     *api,
+		Object:  obj.GetKind() + " " + name,
+		Source:  source,
+		Removed: CompareKubeVersions(api.RemovedIn, target) <= 0,
+	}
+}
+
+// Uses of API versions deprecated by target across the cluster's objects of
+// every kind in the deprecation table. Kinds the cluster doesn't serve are
+// skipped, failing to list one kind doesn't hide the others.
+func (c *UnifiedClient) ScanDeprecations(ctx context.Context, target string) ([]DeprecationFinding, error) {
+	resources, err := c.ListAvailableResources()
+	if err != nil {
+		return nil, err
+	}
+	kinds := make(map[string]bool)
+	for _, api := range DeprecatedAPIs {
+		kinds[api.Kind] = true
+	}
+
+	var findings []DeprecationFinding
+	var errs []error
+	for _, resource := range resources {
+		if resource.IsCustom || !resource.Preferred || !kinds[resource.GVK.Kind] || !resource.HasVerb("list") {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		if err := c.List(ctx, resource.GVR, "", list); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", resource.GVR.Resource, err))
+			continue
+		}
+		for i := range list.Items {
+			list.Items[i].SetKind(resource.GVK.Kind)
+			findings = append(findings, ScanObjectDeprecations(&list.Items[i], target)...)
+		}
+	}
+
+	SortDeprecationFindings(findings)
+	return findings, errors.Join(errs...)
+}
+
+// Removed APIs first, then by the version they go away in and by object
+func SortDeprecationFindings(findings []DeprecationFinding) {
+	slices.SortStableFunc(findings, func(a, b DeprecationFinding) int {
+		if a.Removed != b.Removed {
+			if a.Removed {
+				return -1
+			}
+			return 1
+		}
+		if c := CompareKubeVersions(a.API.RemovedIn, b.API.RemovedIn); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Object, b.Object)
+	})
+}
+
+// Kubernetes minor version of the server, e.g. "v1.30"
+func (c *UnifiedClient) ServerVersion() (string, error) {
+	info, err := c.discoveryClient.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+	// Some distributions report the minor version as e.g. "30+"
+	return fmt.Sprintf("v%s.%s", info.Major, strings.TrimSuffix(info.Minor, "+")), nil
+}
+
+// Version the given number of minor releases later, e.g. "v1.31" for "v1.30"
+// and 1
+func NextKubeVersion(version string, minors int) string {
+	major, minor := parseKubeVersion(version)
+	return fmt.Sprintf("v%d.%d", major, max(0, minor+minors))
+}
+
+// Order Kubernetes versions like "v1.9" and "1.25" numerically
+func CompareKubeVersions(a, b string) int {
+	aMajor, aMinor := parseKubeVersion(a)
+	bMajor, bMinor := parseKubeVersion(b)
+	if aMajor != bMajor {
+		return aMajor - bMajor
+	}
+	return aMinor - bMinor
+}
+
+func parseKubeVersion(version string) (int, int) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(strings.TrimSuffix(parts[1], "+"))
+	}
+	return major, minor
+}
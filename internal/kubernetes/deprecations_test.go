@@ -0,0 +1,212 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseKubeVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		major, minor int
+	}{
+		{"v1.9", 1, 9},
+		{"v1.25", 1, 25},
+		{"1.30", 1, 30},
+		{"v1.30+", 1, 30}, // Minor versions like "30+" from some distributions
+		{"v1.33.2", 1, 33},
+		{"v2", 2, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		major, minor := parseKubeVersion(tt.version)
+		if major != tt.major || minor != tt.minor {
+			t.Errorf("parseKubeVersion(%q) = %d, %d, want %d, %d", tt.version, major, minor, tt.major, tt.minor)
+		}
+	}
+}
+
+func TestCompareKubeVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // Sign of the result
+	}{
+		{"v1.9", "v1.25", -1}, // Numerically, not as strings
+		{"v1.25", "v1.9", 1},
+		{"v1.25", "1.25", 0},
+		{"v1.30+", "v1.30", 0},
+		{"v1.33.2", "v1.33", 0},
+		{"v2.0", "v1.99", 1},
+	}
+	for _, tt := range tests {
+		got := CompareKubeVersions(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("CompareKubeVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestNextKubeVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		minors  int
+		want    string
+	}{
+		{"v1.30", 1, "v1.31"},
+		{"v1.9", 1, "v1.10"},
+		{"v1.30+", 1, "v1.31"},
+		{"v1.30", -1, "v1.29"},
+		{"v1.0", -1, "v1.0"},
+	}
+	for _, tt := range tests {
+		if got := NextKubeVersion(tt.version, tt.minors); got != tt.want {
+			t.Errorf("NextKubeVersion(%q, %d) = %q, want %q", tt.version, tt.minors, got, tt.want)
+		}
+	}
+}
+
+func TestScanManifestDeprecations(t *testing.T) {
+	manifest := `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: old
+  namespace: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: current
+---
+# Empty document
+---
+just a string
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: batch/v1beta1
+  kind: CronJob
+  metadata:
+    name: nightly
+- apiVersion: policy/v1beta1
+  kind: PodDisruptionBudget
+  metadata:
+    name: web
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: settings
+`
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{
+			target: "v1.24",
+			want: []string{
+				"removed Deployment web/old at deploy.yaml:1",
+				"deprecated CronJob nightly at deploy.yaml:16",
+				"deprecated PodDisruptionBudget web at deploy.yaml:16",
+			},
+		},
+		{
+			target: "v1.25",
+			want: []string{
+				"removed Deployment web/old at deploy.yaml:1",
+				"removed CronJob nightly at deploy.yaml:16",
+				"removed PodDisruptionBudget web at deploy.yaml:16",
+			},
+		},
+		{
+			// Before anything in the manifest was deprecated
+			target: "v1.8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			findings, err := ScanManifestDeprecations("deploy.yaml", []byte(manifest), tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFindings(t, findings, tt.want)
+		})
+	}
+}
+
+func TestScanManifestDeprecationsInvalidYAML(t *testing.T) {
+	manifest := "apiVersion: batch/v1beta1\nkind: CronJob\nmetadata: {name: nightly}\n---\nkind: [\n"
+	findings, err := ScanManifestDeprecations("cron.yaml", []byte(manifest), "v1.25")
+	if err == nil || !strings.HasPrefix(err.Error(), "cron.yaml: ") {
+		t.Errorf("error = %v, want one naming the file", err)
+	}
+	// Documents before the broken one are still reported
+	assertFindings(t, findings, []string{"removed CronJob nightly at cron.yaml:1"})
+}
+
+func TestScanObjectDeprecations(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetKind("Ingress")
+	obj.SetName("web")
+	obj.SetNamespace("shop")
+	obj.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"extensions/v1beta1","kind":"Ingress"}`,
+	})
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "networking.k8s.io/v1beta1"},
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "networking.k8s.io/v1beta1"},
+		{Manager: "controller", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "networking.k8s.io/v1"},
+	})
+
+	assertFindings(t, ScanObjectDeprecations(obj, "v1.22"), []string{
+		"removed Ingress shop/web at last-applied-configuration",
+		"removed Ingress shop/web at managedFields (kubectl, Update)",
+	})
+}
+
+func TestSortDeprecationFindings(t *testing.T) {
+	findings := []DeprecationFinding{
+		{API: DeprecatedAPI{RemovedIn: "v1.25"}, Object: "CronJob b"},
+		{API: DeprecatedAPI{RemovedIn: "v1.16"}, Object: "Deployment a", Removed: true},
+		{API: DeprecatedAPI{RemovedIn: "v1.9"}, Object: "Z", Removed: true},
+		{API: DeprecatedAPI{RemovedIn: "v1.25"}, Object: "CronJob a"},
+	}
+	SortDeprecationFindings(findings)
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.Object)
+	}
+	if want := "Z,Deployment a,CronJob a,CronJob b"; strings.Join(got, ",") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+// Compare findings written as "removed|deprecated OBJECT at SOURCE"
+func assertFindings(t *testing.T, findings []DeprecationFinding, want []string) {
+	t.Helper()
+	var got []string
+	for _, finding := range findings {
+		status := "deprecated"
+		if finding.Removed {
+			status = "removed"
+		}
+		got = append(got, fmt.Sprintf("%s %s at %s", status, finding.Object, finding.Source))
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type PodSummary struct {
+	Name  string
+	Phase string
+	Ready bool
+}
+
+type ServiceEndpoint struct {
+	Address string // host:port
+	Pod     string // Pod behind the address, if any
+	Ready   bool
+}
+
+// Objects a describe view shows next to the object itself
+type RelatedObjects struct {
+	SelectorPods []PodSummary      // Pods matched by the object's selector
+	Endpoints    []ServiceEndpoint // Endpoints of a service
+}
+
+// Look up the pods an object selects and, for services, their endpoints.
+// Objects without a selector have no related objects.
+func (c *UnifiedClient) RelatedObjects(ctx context.Context, obj *unstructured.Unstructured) (*RelatedObjects, error) {
+	related := &RelatedObjects{}
+
+	selector, err := objectSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	if selector != nil && !selector.Empty() {
+		pods, err := c.typedClient.CoreV1().Pods(obj.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list selected pods: %w", err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			related.SelectorPods = append(related.SelectorPods, PodSummary{
+				Name:  pod.Name,
+				Phase: string(pod.Status.Phase),
+				Ready: podReady(pod),
+			})
+		}
+	}
+
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Service" {
+		related.Endpoints, err = c.serviceEndpoints(ctx, obj.GetNamespace(), obj.GetName())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return related, nil
+}
+
+// Pod selector of services and workload controllers, nil for other kinds
+func objectSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Service" {
+		selector, _, err := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if err != nil || len(selector) == 0 {
+			return nil, err
+		}
+		return labels.SelectorFromSet(selector), nil
+	}
+
+	switch obj.GetKind() {
+	case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job":
+	default:
+		return nil, nil
+	}
+
+	raw, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var labelSelector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &labelSelector); err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	return metav1.LabelSelectorAsSelector(&labelSelector)
+}
+
+// Addresses of a service's EndpointSlices, ready ones first
+func (c *UnifiedClient) serviceEndpoints(ctx context.Context, namespace, name string) ([]ServiceEndpoint, error) {
+	list, err := c.typedClient.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+
+	var endpoints []ServiceEndpoint
+	for _, slice := range list.Items {
+		for _, endpoint := range slice.Endpoints {
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			pod := ""
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				pod = endpoint.TargetRef.Name
+			}
+			for _, address := range endpoint.Addresses {
+				for _, port := range slice.Ports {
+					if port.Port == nil {
+						continue
+					}
+					endpoints = append(endpoints, ServiceEndpoint{
+						Address: net.JoinHostPort(address, strconv.Itoa(int(*port.Port))),
+						Pod:     pod,
+						Ready:   ready,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(endpoints, func(a, b ServiceEndpoint) int {
+		switch {
+		case a.Ready == b.Ready:
+			return 0
+		case a.Ready:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return endpoints, nil
+}
@@ -0,0 +1,397 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Owners followed up from a pod, more is a loop or a very unusual controller
+const maxOwnerDepth = 5
+
+// Log bytes read per container, in case lines are very long
+const maxDiagnosticLogBytes = 256 * 1024
+
+// What explains a pod's state besides its own YAML, gathered for AI analysis
+type PodDiagnostics struct {
+	Pod        *corev1.Pod
+	Events     []EventRecord // Newest first
+	Logs       []ContainerLog
+	Owners     []OwnerSummary    // Controller chain, nearest first, e.g. ReplicaSet then Deployment
+	References []ReferenceStatus // ConfigMaps and Secrets the pod uses
+	Claims     []ClaimStatus     // PersistentVolumeClaims the pod mounts
+	Node       *NodeSummary      // Nil if the pod isn't scheduled
+	Errors     []string          // Parts that couldn't be collected, e.g. forbidden logs
+}
+
+type ContainerLog struct {
+	Container string
+	Previous  bool // Of the instance before the last restart
+	Text      string
+}
+
+type OwnerSummary struct {
+	Kind       string
+	Name       string
+	Status     []string // e.g. "replicas: 3", "readyReplicas: 1"
+	Conditions []string // type=status, with reason and message if any
+}
+
+type ReferenceStatus struct {
+	Kind     string // ConfigMap or Secret
+	Name     string
+	UsedBy   []string // e.g. "volume config", "env DB_HOST of app"
+	Optional bool
+	Exists   bool
+	Missing  []string // Referenced keys the object doesn't have
+	Error    string   // Set if existence couldn't be checked
+}
+
+type ClaimStatus struct {
+	Name         string
+	Exists       bool
+	Phase        string
+	StorageClass string
+	Capacity     string
+	AccessModes  []string
+	VolumeName   string
+}
+
+type NodeSummary struct {
+	Name          string
+	Unschedulable bool
+	Conditions    []string // type=status, with reason and message if any
+	Taints        []string
+}
+
+// Options of CollectPodDiagnostics
+type DiagnosticsOptions struct {
+	LogLines int64 // Lines of current and previous logs per container
+}
+
+// Gather the events, recent logs, owner chain, referenced ConfigMaps and
+// Secrets, claims and node of a pod. Only the pod itself is required, other
+// parts that fail are listed in Errors. Secret values are never read into
+// the result, only whether the Secret and its referenced keys exist.
+func (c *UnifiedClient) CollectPodDiagnostics(ctx context.Context, namespace, name string, opts DiagnosticsOptions) (*PodDiagnostics, error) {
+	pod, err := c.typedClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	d := &PodDiagnostics{Pod: pod}
+
+	if d.Events, err = c.ListObjectEvents(ctx, namespace, pod.UID); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("events: %v", err))
+	}
+	c.collectLogs(ctx, d, opts.LogLines)
+	c.collectOwners(ctx, d)
+	c.collectReferences(ctx, d)
+	c.collectClaims(ctx, d)
+	c.collectNode(ctx, d)
+	return d, nil
+}
+
+// Current logs of containers that have started, and the previous logs of
+// the ones that restarted, which usually hold the crash
+func (c *UnifiedClient) collectLogs(ctx context.Context, d *PodDiagnostics, lines int64) {
+	if lines <= 0 {
+		return
+	}
+	statuses := append(slices.Clone(d.Pod.Status.InitContainerStatuses), d.Pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Running != nil || status.State.Terminated != nil {
+			c.collectLog(ctx, d, status.Name, false, lines)
+		}
+		if status.LastTerminationState.Terminated != nil {
+			c.collectLog(ctx, d, status.Name, true, lines)
+		}
+	}
+}
+
+func (c *UnifiedClient) collectLog(ctx context.Context, d *PodDiagnostics, container string, previous bool, lines int64) {
+	stream, err := c.StreamLogs(ctx, d.Pod.Namespace, d.Pod.Name, LogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &lines,
+	})
+	if err == nil {
+		var data []byte
+		data, err = io.ReadAll(io.LimitReader(stream, maxDiagnosticLogBytes))
+		stream.Close()
+		if err == nil {
+			d.Logs = append(d.Logs, ContainerLog{Container: container, Previous: previous, Text: string(data)})
+			return
+		}
+	}
+	kind := "logs"
+	if previous {
+		kind = "previous logs"
+	}
+	d.Errors = append(d.Errors, fmt.Sprintf("%s of %s: %v", kind, container, err))
+}
+
+// Follow controller owner references up from the pod
+func (c *UnifiedClient) collectOwners(ctx context.Context, d *PodDiagnostics) {
+	namespace := d.Pod.Namespace
+	owner := metav1.GetControllerOf(d.Pod)
+	for depth := 0; owner != nil && depth < maxOwnerDepth; depth++ {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("owner %s/%s: %v", owner.Kind, owner.Name, err))
+			return
+		}
+		info, err := c.ResourceForGVK(gv.WithKind(owner.Kind))
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("owner %s/%s: %v", owner.Kind, owner.Name, err))
+			return
+		}
+		if !info.Namespaced {
+			namespace = ""
+		}
+
+		var obj unstructured.Unstructured
+		if err := c.Get(ctx, info.GVR, namespace, owner.Name, &obj); err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("owner %s/%s: %v", owner.Kind, owner.Name, err))
+			return
+		}
+		d.Owners = append(d.Owners, ownerSummary(&obj))
+		owner = metav1.GetControllerOf(&obj)
+	}
+}
+
+// Replica counts and conditions, the parts of a controller's status that
+// say whether it is healthy
+func ownerSummary(obj *unstructured.Unstructured) OwnerSummary {
+	summary := OwnerSummary{Kind: obj.GetKind(), Name: obj.GetName()}
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		summary.Status = append(summary.Status, fmt.Sprintf("replicas: %d", replicas))
+	}
+	for _, field := range []string{"readyReplicas", "availableReplicas", "updatedReplicas", "unavailableReplicas", "active", "failed", "succeeded"} {
+		if value, found, _ := unstructured.NestedInt64(obj.Object, "status", field); found {
+			summary.Status = append(summary.Status, fmt.Sprintf("%s: %d", field, value))
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		summary.Conditions = append(summary.Conditions, conditionText(
+			fmt.Sprint(condition["type"]), fmt.Sprint(condition["status"]),
+			stringField(condition, "reason"), stringField(condition, "message"),
+		))
+	}
+	return summary
+}
+
+func stringField(obj map[string]any, field string) string {
+	value, _ := obj[field].(string)
+	return value
+}
+
+func conditionText(conditionType, status, reason, message string) string {
+	text := conditionType + "=" + status
+	if reason != "" {
+		text += " " + reason
+	}
+	if message != "" {
+		text += ": " + message
+	}
+	return text
+}
+
+// A ConfigMap or Secret reference found in the pod spec
+type objectReference struct {
+	kind     string
+	name     string
+	key      string // Empty for references to the whole object
+	usedBy   string
+	optional bool
+}
+
+func podObjectReferences(pod *corev1.Pod) []objectReference {
+	var refs []objectReference
+	isOptional := func(optional *bool) bool { return optional != nil && *optional }
+
+	for _, volume := range pod.Spec.Volumes {
+		usedBy := "volume " + volume.Name
+		switch {
+		case volume.ConfigMap != nil:
+			refs = append(refs, objectReference{kind: "ConfigMap", name: volume.ConfigMap.Name, usedBy: usedBy, optional: isOptional(volume.ConfigMap.Optional)})
+		case volume.Secret != nil:
+			refs = append(refs, objectReference{kind: "Secret", name: volume.Secret.SecretName, usedBy: usedBy, optional: isOptional(volume.Secret.Optional)})
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					refs = append(refs, objectReference{kind: "ConfigMap", name: source.ConfigMap.Name, usedBy: usedBy, optional: isOptional(source.ConfigMap.Optional)})
+				}
+				if source.Secret != nil {
+					refs = append(refs, objectReference{kind: "Secret", name: source.Secret.Name, usedBy: usedBy, optional: isOptional(source.Secret.Optional)})
+				}
+			}
+		}
+	}
+
+	containers := append(slices.Clone(pod.Spec.InitContainers), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, source := range container.EnvFrom {
+			usedBy := "envFrom of " + container.Name
+			if source.ConfigMapRef != nil {
+				refs = append(refs, objectReference{kind: "ConfigMap", name: source.ConfigMapRef.Name, usedBy: usedBy, optional: isOptional(source.ConfigMapRef.Optional)})
+			}
+			if source.SecretRef != nil {
+				refs = append(refs, objectReference{kind: "Secret", name: source.SecretRef.Name, usedBy: usedBy, optional: isOptional(source.SecretRef.Optional)})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			usedBy := fmt.Sprintf("env %s of %s", env.Name, container.Name)
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs = append(refs, objectReference{kind: "ConfigMap", name: ref.Name, key: ref.Key, usedBy: usedBy, optional: isOptional(ref.Optional)})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs = append(refs, objectReference{kind: "Secret", name: ref.Name, key: ref.Key, usedBy: usedBy, optional: isOptional(ref.Optional)})
+			}
+		}
+	}
+
+	for _, secret := range pod.Spec.ImagePullSecrets {
+		refs = append(refs, objectReference{kind: "Secret", name: secret.Name, usedBy: "imagePullSecrets"})
+	}
+	return refs
+}
+
+// Check that referenced ConfigMaps and Secrets and their keys exist. Only
+// key names are looked at, values stay out of the result.
+func (c *UnifiedClient) collectReferences(ctx context.Context, d *PodDiagnostics) {
+	byObject := make(map[string]*ReferenceStatus)
+	keys := make(map[string][]string)
+	var order []string
+	for _, ref := range podObjectReferences(d.Pod) {
+		id := ref.kind + "/" + ref.name
+		status, ok := byObject[id]
+		if !ok {
+			status = &ReferenceStatus{Kind: ref.kind, Name: ref.name, Optional: true}
+			byObject[id] = status
+			order = append(order, id)
+		}
+		status.UsedBy = append(status.UsedBy, ref.usedBy)
+		status.Optional = status.Optional && ref.optional
+		if ref.key != "" && !slices.Contains(keys[id], ref.key) {
+			keys[id] = append(keys[id], ref.key)
+		}
+	}
+
+	namespace := d.Pod.Namespace
+	for _, id := range order {
+		status := byObject[id]
+		var present func(key string) bool
+		var err error
+		if status.Kind == "ConfigMap" {
+			var configMap *corev1.ConfigMap
+			configMap, err = c.typedClient.CoreV1().ConfigMaps(namespace).Get(ctx, status.Name, metav1.GetOptions{})
+			if err == nil {
+				present = func(key string) bool {
+					_, inData := configMap.Data[key]
+					_, inBinary := configMap.BinaryData[key]
+					return inData || inBinary
+				}
+			}
+		} else {
+			var secret *corev1.Secret
+			secret, err = c.typedClient.CoreV1().Secrets(namespace).Get(ctx, status.Name, metav1.GetOptions{})
+			if err == nil {
+				present = func(key string) bool {
+					_, ok := secret.Data[key]
+					return ok
+				}
+			}
+		}
+
+		switch {
+		case err == nil:
+			status.Exists = true
+			for _, key := range keys[id] {
+				if !present(key) {
+					status.Missing = append(status.Missing, key)
+				}
+			}
+		case apierrors.IsNotFound(err):
+		default:
+			status.Error = err.Error()
+		}
+		d.References = append(d.References, *status)
+	}
+}
+
+func (c *UnifiedClient) collectClaims(ctx context.Context, d *PodDiagnostics) {
+	for _, volume := range d.Pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		name := volume.PersistentVolumeClaim.ClaimName
+		pvc, err := c.typedClient.CoreV1().PersistentVolumeClaims(d.Pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			d.Claims = append(d.Claims, ClaimStatus{Name: name})
+			continue
+		}
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("claim %s: %v", name, err))
+			continue
+		}
+
+		claim := ClaimStatus{
+			Name:       name,
+			Exists:     true,
+			Phase:      string(pvc.Status.Phase),
+			VolumeName: pvc.Spec.VolumeName,
+		}
+		if pvc.Spec.StorageClassName != nil {
+			claim.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			claim.Capacity = capacity.String()
+		}
+		for _, mode := range pvc.Spec.AccessModes {
+			claim.AccessModes = append(claim.AccessModes, string(mode))
+		}
+		d.Claims = append(d.Claims, claim)
+	}
+}
+
+func (c *UnifiedClient) collectNode(ctx context.Context, d *PodDiagnostics) {
+	if d.Pod.Spec.NodeName == "" {
+		return
+	}
+	node, err := c.typedClient.CoreV1().Nodes().Get(ctx, d.Pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("node %s: %v", d.Pod.Spec.NodeName, err))
+		return
+	}
+
+	summary := &NodeSummary{Name: node.Name, Unschedulable: node.Spec.Unschedulable}
+	for _, condition := range node.Status.Conditions {
+		summary.Conditions = append(summary.Conditions, conditionText(
+			string(condition.Type), string(condition.Status), condition.Reason, condition.Message,
+		))
+	}
+	for _, taint := range node.Spec.Taints {
+		text := taint.Key
+		if taint.Value != "" {
+			text += "=" + taint.Value
+		}
+		summary.Taints = append(summary.Taints, text+":"+string(taint.Effect))
+	}
+	d.Node = summary
+}
@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Failed API request, keeping the server's status reason so callers can
+// react to conflicts, missing permissions or missing objects. Wraps the
+// original *apierrors.StatusError, so the apierrors helpers work on it too.
+type APIError struct {
+	Op        string // "create", "update", "patch", "delete" or "apply"
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Name      string
+	Reason    metav1.StatusReason
+	Err       error
+}
+
+func (e *APIError) Error() string {
+	target := e.Name
+	if e.Namespace != "" {
+		target = e.Namespace + "/" + e.Name
+	}
+	return fmt.Sprintf("%s %s %s: %v", e.Op, e.GVR.Resource, target, e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func newAPIError(op string, gvr schema.GroupVersionResource, namespace, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &APIError{
+		Op:        op,
+		GVR:       gvr,
+		Namespace: namespace,
+		Name:      name,
+		Reason:    apierrors.ReasonForError(err),
+		Err:       err,
+	}
+}
+
+// The object was modified since it was read, or a field is owned by another manager
+func IsConflict(err error) bool {
+	return apierrors.IsConflict(err)
+}
+
+func IsForbidden(err error) bool {
+	return apierrors.IsForbidden(err)
+}
+
+func IsNotFound(err error) bool {
+	return apierrors.IsNotFound(err)
+}
+
+func IsAlreadyExists(err error) bool {
+	return apierrors.IsAlreadyExists(err)
+}
+
+// The object failed server-side validation
+func IsInvalid(err error) bool {
+	return apierrors.IsInvalid(err)
+}
+
+// Human readable explanation for API errors the UI can act on
+func ErrorHint(err error) string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+
+	switch {
+	case IsConflict(err):
+		return "The object was changed by someone else, reload it and try again."
+	case IsForbidden(err):
+		return fmt.Sprintf("You are not allowed to %s %s in this namespace.", apiErr.Op, apiErr.GVR.Resource)
+	case IsNotFound(err):
+		return "The object no longer exists."
+	case IsAlreadyExists(err):
+		return "An object with this name already exists."
+	case IsInvalid(err):
+		return "The server rejected the object, check the field errors."
+	}
+	return ""
+}
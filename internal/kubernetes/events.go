@@ -0,0 +1,91 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Event flattened across the events.k8s.io/v1 fields and their deprecated
+// core/v1 counterparts, which older reporters still fill in
+type EventRecord struct {
+	Type      string // Normal or Warning
+	Reason    string
+	Message   string
+	Count     int32
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Source    string // Reporting controller, e.g. "kubelet"
+	Object    string // Regarding object as kind/name
+	UID       types.UID
+}
+
+// Events about a single object, newest first. Cluster-scoped objects have
+// their events spread over namespaces, so an empty namespace lists all of them.
+func (c *UnifiedClient) ListObjectEvents(ctx context.Context, namespace string, uid types.UID) ([]EventRecord, error) {
+	return c.listEvents(ctx, namespace, fields.OneTermEqualSelector("regarding.uid", string(uid)).String())
+}
+
+// All events in a namespace, newest first
+func (c *UnifiedClient) ListNamespaceEvents(ctx context.Context, namespace string) ([]EventRecord, error) {
+	return c.listEvents(ctx, namespace, "")
+}
+
+func (c *UnifiedClient) listEvents(ctx context.Context, namespace, fieldSelector string) ([]EventRecord, error) {
+	list, err := c.typedClient.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]EventRecord, 0, len(list.Items))
+	for i := range list.Items {
+		records = append(records, eventRecord(&list.Items[i]))
+	}
+
+	slices.SortStableFunc(records, func(a, b EventRecord) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	return records, nil
+}
+
+func eventRecord(event *eventsv1.Event) EventRecord {
+	record := EventRecord{
+		Type:    event.Type,
+		Reason:  event.Reason,
+		Message: event.Note,
+		Count:   1,
+		Source:  event.ReportingController,
+		Object:  event.Regarding.Kind + "/" + event.Regarding.Name,
+		UID:     event.Regarding.UID,
+	}
+	if record.Source == "" {
+		record.Source = event.DeprecatedSource.Component
+	}
+
+	// First and last occurrence, preferring the newer fields
+	record.FirstSeen = event.EventTime.Time
+	if record.FirstSeen.IsZero() {
+		record.FirstSeen = event.DeprecatedFirstTimestamp.Time
+	}
+	if record.FirstSeen.IsZero() {
+		record.FirstSeen = event.CreationTimestamp.Time
+	}
+	record.LastSeen = event.DeprecatedLastTimestamp.Time
+	if record.LastSeen.IsZero() {
+		record.LastSeen = record.FirstSeen
+	}
+
+	if event.Series != nil {
+		record.Count = event.Series.Count
+		record.LastSeen = event.Series.LastObservedTime.Time
+	} else if event.DeprecatedCount > 0 {
+		record.Count = event.DeprecatedCount
+	}
+
+	return record
+}
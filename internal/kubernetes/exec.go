@@ -0,0 +1,94 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Width and height of a TTY, as reported to the remote process
+type TerminalSize = remotecommand.TerminalSize
+
+type ExecOptions struct {
+	Container string
+	Command   []string
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer // Unused with TTY, the terminal merges stderr into stdout
+	TTY       bool
+
+	// Terminal resizes to propagate to the remote TTY, nil when not interactive
+	SizeQueue remotecommand.TerminalSizeQueue
+}
+
+// Run a command in a pod container, streaming stdio until it exits. Uses the
+// WebSocket protocol, falling back to SPDY for API servers that lack it.
+func (c *UnifiedClient) Exec(ctx context.Context, namespace, pod string, opts ExecOptions) error {
+	req := c.typedClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil && !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(c.config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: opts.SizeQueue,
+	}
+	if !opts.TTY {
+		streamOptions.Stderr = opts.Stderr
+	}
+
+	return executor.StreamWithContext(ctx, streamOptions)
+}
+
+// Containers that can be exec'd into: running regular and ephemeral containers
+func RunningContainers(pod *corev1.Pod) []PodContainer {
+	running := make(map[string]bool)
+	for _, statuses := range [][]corev1.ContainerStatus{
+		pod.Status.ContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for _, status := range statuses {
+			running[status.Name] = status.State.Running != nil
+		}
+	}
+
+	var containers []PodContainer
+	for _, container := range PodContainers(pod) {
+		if container.Type != InitContainer && running[container.Name] {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
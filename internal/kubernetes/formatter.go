@@ -1,9 +1,57 @@
 package kubernetes
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Time since t the way kubectl shows ages, e.g. "5m" or "3d2h"
+func Age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
 
 func CleanData(obj unstructured.Unstructured) unstructured.Unstructured {
 
 	delete(obj.Object["metadata"].(map[string]any), "managedFields")
 	return obj
 }
+
+// Metadata fields the API server maintains, which a manifest should not carry
+var serverMetadataFields = []string{
+	"managedFields", "resourceVersion", "uid", "generation", "selfLink",
+	"creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds",
+}
+
+// Strip server-maintained metadata, which changes on every write, to compare
+// or diff versions of an object by their content
+func ComparableData(obj unstructured.Unstructured) unstructured.Unstructured {
+	if metadata, ok := obj.Object["metadata"].(map[string]any); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+	}
+	return obj
+}
+
+// Strip status and server-maintained metadata, leaving a manifest that can
+// be edited and applied again
+func EditableData(obj unstructured.Unstructured) unstructured.Unstructured {
+	obj = ComparableData(obj)
+	delete(obj.Object, "status")
+
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+		} else {
+			obj.SetAnnotations(annotations)
+		}
+	}
+	return obj
+}
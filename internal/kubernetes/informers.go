@@ -0,0 +1,157 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ResourceEventType string
+
+const (
+	ResourceAdded   ResourceEventType = "added"
+	ResourceUpdated ResourceEventType = "updated"
+	ResourceDeleted ResourceEventType = "deleted"
+)
+
+// Change to a watched resource, delivered by InformerManager
+type ResourceEvent struct {
+	Type   ResourceEventType
+	GVR    schema.GroupVersionResource
+	Object *unstructured.Unstructured
+}
+
+type informerKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+type informerEntry struct {
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+	handlers int
+}
+
+// Shares one dynamic informer per GVR and namespace between all watchers,
+// stopping it once the last watcher is gone
+type InformerManager struct {
+	client    *UnifiedClient
+	informers map[informerKey]*informerEntry
+	mutex     sync.Mutex
+}
+
+func NewInformerManager(client *UnifiedClient) *InformerManager {
+	return &InformerManager{
+		client:    client,
+		informers: make(map[informerKey]*informerEntry),
+	}
+}
+
+// Watch a resource type in a namespace ("" for all namespaces or cluster-scoped
+// resources). The handler is called from informer goroutines, starting with an
+// add event for every existing object. The returned function stops the watch.
+func (m *InformerManager) Watch(gvr schema.GroupVersionResource, namespace string, handler func(ResourceEvent)) (func(), error) {
+	resourceInfo, err := m.client.getResourceInfo(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate namespace usage
+	if namespace != "" && !resourceInfo.Namespaced {
+		return nil, fmt.Errorf("resource %v is cluster-scoped, cannot specify namespace", gvr)
+	}
+	if !resourceInfo.HasVerb("watch") {
+		return nil, fmt.Errorf("resource %v does not support watch", gvr)
+	}
+
+	key := informerKey{gvr: gvr, namespace: namespace}
+	entry := m.acquire(key)
+
+	registration, err := entry.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				handler(ResourceEvent{Type: ResourceAdded, GVR: gvr, Object: u})
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			if u, ok := newObj.(*unstructured.Unstructured); ok {
+				handler(ResourceEvent{Type: ResourceUpdated, GVR: gvr, Object: u})
+			}
+		},
+		DeleteFunc: func(obj any) {
+			// Deletions missed while disconnected arrive wrapped in a tombstone
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				handler(ResourceEvent{Type: ResourceDeleted, GVR: gvr, Object: u})
+			}
+		},
+	})
+	if err != nil {
+		m.release(key, entry)
+		return nil, err
+	}
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			_ = entry.informer.RemoveEventHandler(registration)
+			m.release(key, entry)
+		})
+	}
+
+	return stop, nil
+}
+
+// Stop every running informer
+func (m *InformerManager) Shutdown() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, entry := range m.informers {
+		close(entry.stopCh)
+		delete(m.informers, key)
+	}
+}
+
+func (m *InformerManager) acquire(key informerKey) *informerEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if entry, exists := m.informers[key]; exists {
+		entry.handlers++
+		return entry
+	}
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.client.dynamicClient, 0, key.namespace, nil)
+	entry := &informerEntry{
+		informer: factory.ForResource(key.gvr).Informer(),
+		stopCh:   make(chan struct{}),
+		handlers: 1,
+	}
+	factory.Start(entry.stopCh)
+
+	m.informers[key] = entry
+	return entry
+}
+
+func (m *InformerManager) release(key informerKey, entry *informerEntry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// The entry may already be gone after Shutdown
+	if m.informers[key] != entry {
+		return
+	}
+
+	entry.handlers--
+	if entry.handlers <= 0 {
+		close(entry.stopCh)
+		delete(m.informers, key)
+	}
+}
@@ -0,0 +1,91 @@
+package kubernetes
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Options selecting which kubeconfig and context the client connects with
+type ClientOptions struct {
+	Kubeconfig string // Explicit kubeconfig path, overrides KUBECONFIG
+	Context    string // Context name, defaults to the kubeconfig's current-context
+}
+
+// Namespace of the pod's service account, mounted into every pod
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Loading rules honour KUBECONFIG merging unless an explicit path is given
+func (o ClientOptions) loadingRules() *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if o.Kubeconfig != "" {
+		rules.ExplicitPath = o.Kubeconfig
+	}
+	return rules
+}
+
+func (o ClientOptions) clientConfig() clientcmd.ClientConfig {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(o.loadingRules(), overrides)
+}
+
+// Resolve the rest config, context name and default namespace for the options.
+// Falls back to the in-cluster config when no kubeconfig is usable.
+func loadRestConfig(opts ClientOptions) (*rest.Config, string, string, error) {
+	clientConfig := opts.clientConfig()
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		inClusterConfig, inClusterErr := rest.InClusterConfig()
+		if inClusterErr != nil {
+			return nil, "", "", err
+		}
+		return inClusterConfig, "in-cluster", inClusterNamespace(), nil
+	}
+
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	contextName := opts.Context
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return config, contextName, namespace, nil
+}
+
+// Namespace the pod runs in, "default" when the service account doesn't say
+func inClusterNamespace() string {
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if namespace := strings.TrimSpace(string(data)); err == nil && namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// List the context names in the merged kubeconfig along with the current-context
+func ListContexts(opts ClientOptions) ([]string, string, error) {
+	rawConfig, err := opts.clientConfig().RawConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	var contexts []string
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, rawConfig.CurrentContext, nil
+}
@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotation kubectl uses to pick the container for logs and exec
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+type ContainerType string
+
+const (
+	InitContainer      ContainerType = "init"
+	RegularContainer   ContainerType = "container"
+	EphemeralContainer ContainerType = "ephemeral"
+)
+
+type PodContainer struct {
+	Name         string
+	Type         ContainerType
+	RestartCount int32
+}
+
+type LogOptions struct {
+	Container  string
+	Follow     bool
+	Previous   bool // Logs of the previous, terminated instance of the container
+	Timestamps bool
+	SinceTime  *time.Time
+	TailLines  *int64
+}
+
+// Stream a container's logs, following new output when opts.Follow is set.
+// Cancelling ctx closes the stream.
+func (c *UnifiedClient) StreamLogs(ctx context.Context, namespace, pod string, opts LogOptions) (io.ReadCloser, error) {
+	podLogOptions := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Follow:     opts.Follow,
+		Previous:   opts.Previous,
+		Timestamps: opts.Timestamps,
+		TailLines:  opts.TailLines,
+	}
+	if opts.SinceTime != nil {
+		sinceTime := metav1.NewTime(*opts.SinceTime)
+		podLogOptions.SinceTime = &sinceTime
+	}
+
+	return c.typedClient.CoreV1().Pods(namespace).GetLogs(pod, podLogOptions).Stream(ctx)
+}
+
+// List init, regular and ephemeral containers of a pod, in that order
+func PodContainers(pod *corev1.Pod) []PodContainer {
+	restarts := make(map[string]int32)
+	for _, statuses := range [][]corev1.ContainerStatus{
+		pod.Status.InitContainerStatuses,
+		pod.Status.ContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for _, status := range statuses {
+			restarts[status.Name] = status.RestartCount
+		}
+	}
+
+	var containers []PodContainer
+	for _, container := range pod.Spec.InitContainers {
+		containers = append(containers, PodContainer{Name: container.Name, Type: InitContainer, RestartCount: restarts[container.Name]})
+	}
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, PodContainer{Name: container.Name, Type: RegularContainer, RestartCount: restarts[container.Name]})
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		containers = append(containers, PodContainer{Name: container.Name, Type: EphemeralContainer, RestartCount: restarts[container.Name]})
+	}
+
+	return containers
+}
+
+// Container kubectl would pick: the default-container annotation, else the first regular container
+func DefaultContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
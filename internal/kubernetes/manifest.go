@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Decode a multi-document YAML or JSON manifest. Empty documents are skipped
+// and List kinds are expanded into their items.
+func ParseManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+
+	var objects []*unstructured.Unstructured
+	for document := 1; ; document++ {
+		var raw map[string]any
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", document, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("document %d: apiVersion and kind are required", document)
+		}
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("document %d: %s has no metadata.name", document, obj.GetKind())
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest contains no objects")
+	}
+	return objects, nil
+}
+
+type ApplyAction string
+
+const (
+	ApplyCreate    ApplyAction = "create"
+	ApplyUpdate    ApplyAction = "update"
+	ApplyUnchanged ApplyAction = "unchanged"
+	ApplyFailed    ApplyAction = "error"
+)
+
+// What applying one object of a manifest would do, as computed by a server dry run
+type ApplyPlanItem struct {
+	Object    *unstructured.Unstructured // As written in the manifest
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Live      *unstructured.Unstructured // Current object, nil when it would be created
+	DryRun    *unstructured.Unstructured // Object the server would store
+	Action    ApplyAction
+	Err       error // Why the dry run failed, an *ApplyConflictError for ownership conflicts
+}
+
+// Kind and namespaced name, e.g. "Deployment default/web"
+func (p *ApplyPlanItem) String() string {
+	name := p.Object.GetName()
+	if p.Namespace != "" {
+		name = p.Namespace + "/" + name
+	}
+	return p.Object.GetKind() + " " + name
+}
+
+// Dry-run apply every object and compare the result with the live object.
+// Namespaced objects without a namespace go to defaultNamespace.
+func (c *UnifiedClient) PlanApply(ctx context.Context, objects []*unstructured.Unstructured, defaultNamespace string, opts ApplyOptions) []*ApplyPlanItem {
+	opts.DryRun = true
+
+	plan := make([]*ApplyPlanItem, 0, len(objects))
+	for _, obj := range objects {
+		item := &ApplyPlanItem{Object: obj}
+		plan = append(plan, item)
+
+		resourceInfo, err := c.ResourceForGVK(obj.GroupVersionKind())
+		if err != nil {
+			item.Action, item.Err = ApplyFailed, err
+			continue
+		}
+		item.GVR = resourceInfo.GVR
+		if resourceInfo.Namespaced {
+			item.Namespace = obj.GetNamespace()
+			if item.Namespace == "" {
+				item.Namespace = defaultNamespace
+			}
+		}
+
+		live := &unstructured.Unstructured{}
+		if err := c.Get(ctx, item.GVR, item.Namespace, obj.GetName(), live); err == nil {
+			item.Live = live
+		} else if !IsNotFound(err) {
+			item.Action, item.Err = ApplyFailed, err
+			continue
+		}
+
+		item.DryRun, err = c.Apply(ctx, item.GVR, item.Namespace, obj, opts)
+		switch {
+		case err != nil:
+			item.Action, item.Err = ApplyFailed, err
+		case item.Live == nil:
+			item.Action = ApplyCreate
+		case reflect.DeepEqual(comparableObject(item.Live), comparableObject(item.DryRun)):
+			item.Action = ApplyUnchanged
+		default:
+			item.Action = ApplyUpdate
+		}
+	}
+
+	return plan
+}
+
+// Object content without the bookkeeping an apply changes even when nothing
+// else does, such as managedFields of a new field manager
+func comparableObject(obj *unstructured.Unstructured) map[string]any {
+	return ComparableData(*obj.DeepCopy()).Object
+}
@@ -0,0 +1,242 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// Pod port a forward connects to, resolved from a pod or a service
+type PortForwardTarget struct {
+	Namespace     string
+	Pod           string
+	Source        string // Resource the port was picked from, e.g. "svc/web" or "pod/web-0"
+	PortName      string
+	Port          int32 // Port as shown to the user, the service port for services
+	ContainerPort int32
+}
+
+func (t PortForwardTarget) String() string {
+	label := fmt.Sprintf("%d", t.Port)
+	if t.Port != t.ContainerPort {
+		label = fmt.Sprintf("%d -> %d", t.Port, t.ContainerPort)
+	}
+	if t.PortName != "" {
+		label = fmt.Sprintf("%s (%s)", label, t.PortName)
+	}
+	return label
+}
+
+// Running forward from a local port to a pod port
+type PortForward struct {
+	Target    PortForwardTarget
+	LocalPort int32
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// Stop the forward and wait for its listener to close
+func (f *PortForward) Stop() {
+	f.stopOnce.Do(func() {
+		close(f.stopCh)
+	})
+	<-f.done
+}
+
+// Closed once the forward has stopped, either by Stop or because the
+// connection to the pod was lost
+func (f *PortForward) Done() <-chan struct{} {
+	return f.done
+}
+
+// Why the forward stopped, nil while running or after Stop
+func (f *PortForward) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Forward a local port on localhost to the target's container port, returning
+// once the listener is up. A local port of 0 picks a free port.
+func (c *UnifiedClient) StartPortForward(target PortForwardTarget, localPort int32) (*PortForward, error) {
+	req := c.typedClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(target.Namespace).
+		Name(target.Pod).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, err
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	websocketDialer, err := portforward.NewSPDYOverWebsocketDialer(req.URL(), c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := portforward.NewFallbackDialer(websocketDialer, spdyDialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+
+	forward := &PortForward{
+		Target:    target,
+		LocalPort: localPort,
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	readyCh := make(chan struct{})
+
+	ports := []string{fmt.Sprintf("%d:%d", localPort, target.ContainerPort)}
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, ports, forward.stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(forward.done)
+		forward.err = forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case <-forward.done:
+		if forward.err == nil {
+			forward.err = fmt.Errorf("port forward to %s/%s closed before it was ready", target.Namespace, target.Pod)
+		}
+		return nil, forward.err
+	}
+
+	if forwarded, err := forwarder.GetPorts(); err == nil && len(forwarded) > 0 {
+		forward.LocalPort = int32(forwarded[0].Local)
+	}
+
+	return forward, nil
+}
+
+// Ports a pod declares for its containers. Only TCP can be forwarded.
+func PodForwardTargets(pod *corev1.Pod) []PortForwardTarget {
+	var targets []PortForwardTarget
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+				continue
+			}
+			targets = append(targets, PortForwardTarget{
+				Namespace:     pod.Namespace,
+				Pod:           pod.Name,
+				Source:        "pod/" + pod.Name,
+				PortName:      port.Name,
+				Port:          port.ContainerPort,
+				ContainerPort: port.ContainerPort,
+			})
+		}
+	}
+	return targets
+}
+
+// Resolve a service's ports to container ports of one of its ready pods,
+// mapping named targetPorts the way kubectl port-forward does
+func (c *UnifiedClient) ServiceForwardTargets(ctx context.Context, namespace, name string) ([]PortForwardTarget, error) {
+	service, err := c.typedClient.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := c.readyServicePod(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []PortForwardTarget
+	for _, port := range service.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		containerPort, err := resolveTargetPort(port, pod)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, PortForwardTarget{
+			Namespace:     pod.Namespace,
+			Pod:           pod.Name,
+			Source:        "svc/" + service.Name,
+			PortName:      port.Name,
+			Port:          port.Port,
+			ContainerPort: containerPort,
+		})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("service %s has no TCP ports that map to pod %s", name, pod.Name)
+	}
+	return targets, nil
+}
+
+// First running, ready pod selected by the service
+func (c *UnifiedClient) readyServicePod(ctx context.Context, service *corev1.Service) (*corev1.Pod, error) {
+	if len(service.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s has no selector", service.Name)
+	}
+
+	pods, err := c.typedClient.CoreV1().Pods(service.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for service %s: %w", service.Name, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning && podReady(pod) {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("service %s has no ready pods", service.Name)
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Container port a service port sends traffic to on the given pod
+func resolveTargetPort(port corev1.ServicePort, pod *corev1.Pod) (int32, error) {
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == port.TargetPort.StrVal && (containerPort.Protocol == "" || containerPort.Protocol == port.Protocol) {
+					return containerPort.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no container port named %q", pod.Name, port.TargetPort.StrVal)
+	case port.TargetPort.IntVal != 0:
+		return port.TargetPort.IntVal, nil
+	default:
+		// An unset targetPort defaults to the service port
+		return port.Port, nil
+	}
+}
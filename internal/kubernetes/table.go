@@ -0,0 +1,254 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Ask for the server-side Table rendering, accepting a plain list from
+// aggregated APIs that cannot produce one
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+type TableColumn struct {
+	Name string
+	Type string // OpenAPI type of the column, e.g. "string", "integer" or "date"
+}
+
+// Row of a resource table, identifying the object it was rendered from
+type ResourceRow struct {
+	GVR             schema.GroupVersionResource
+	Kind            string
+	Namespace       string
+	Name            string
+	UID             types.UID
+	ResourceVersion string
+	Cells           []string
+}
+
+type ResourceTable struct {
+	Columns []TableColumn
+	Rows    []ResourceRow
+}
+
+// Look up a cell by column name, case-insensitively
+func (t *ResourceTable) Cell(row ResourceRow, column string) (string, bool) {
+	for i, col := range t.Columns {
+		if strings.EqualFold(col.Name, column) && i < len(row.Cells) {
+			return row.Cells[i], true
+		}
+	}
+	return "", false
+}
+
+// List resources as rendered by the API server's Table representation, the
+// same columns kubectl get shows. Custom resources without server-side tables
+// fall back to their additionalPrinterColumns.
+func (c *UnifiedClient) ListTable(ctx context.Context, gvr schema.GroupVersionResource, namespace string) (*ResourceTable, error) {
+	resourceInfo, err := c.getResourceInfo(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate namespace usage
+	if namespace != "" && !resourceInfo.Namespaced {
+		return nil, fmt.Errorf("resource %v is cluster-scoped, cannot specify namespace", gvr)
+	}
+
+	return c.fetchTable(ctx, resourceInfo, namespace)
+}
+
+func (c *UnifiedClient) fetchTable(ctx context.Context, resourceInfo *ResourceInfo, namespace string) (*ResourceTable, error) {
+	body, err := c.discoveryClient.RESTClient().Get().
+		AbsPath(resourcePath(resourceInfo.GVR, namespace)).
+		Param("includeObject", "Metadata").
+		SetHeader("Accept", tableAcceptHeader).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(body, &typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if typeMeta.Kind == "Table" {
+		var table metav1.Table
+		if err := json.Unmarshal(body, &table); err != nil {
+			return nil, fmt.Errorf("failed to decode table: %w", err)
+		}
+		return convertTable(resourceInfo, &table), nil
+	}
+
+	// No server-side table, render the plain list ourselves
+	var list unstructured.UnstructuredList
+	if err := list.UnmarshalJSON(body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return c.buildTable(ctx, resourceInfo, list.Items), nil
+}
+
+// Convert the server's Table, keeping only the default (priority 0) columns
+func convertTable(resourceInfo *ResourceInfo, table *metav1.Table) *ResourceTable {
+	result := &ResourceTable{}
+
+	var columnIndexes []int
+	for i, col := range table.ColumnDefinitions {
+		if col.Priority != 0 {
+			continue
+		}
+		columnIndexes = append(columnIndexes, i)
+		result.Columns = append(result.Columns, TableColumn{Name: strings.ToUpper(col.Name), Type: col.Type})
+	}
+
+	for _, tableRow := range table.Rows {
+		row := ResourceRow{GVR: resourceInfo.GVR, Kind: resourceInfo.GVK.Kind}
+
+		var metadata metav1.PartialObjectMetadata
+		if err := json.Unmarshal(tableRow.Object.Raw, &metadata); err == nil {
+			row.Namespace = metadata.Namespace
+			row.Name = metadata.Name
+			row.UID = metadata.UID
+			row.ResourceVersion = metadata.ResourceVersion
+		}
+
+		for _, i := range columnIndexes {
+			var cell any
+			if i < len(tableRow.Cells) {
+				cell = tableRow.Cells[i]
+			}
+			row.Cells = append(row.Cells, formatCell(cell, table.ColumnDefinitions[i].Type))
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
+
+// Build a table from plain objects using the CRD's additionalPrinterColumns,
+// or just name and age when none are defined
+func (c *UnifiedClient) buildTable(ctx context.Context, resourceInfo *ResourceInfo, items []unstructured.Unstructured) *ResourceTable {
+	type printerColumn struct {
+		TableColumn
+		parser *jsonpath.JSONPath
+	}
+
+	var columns []printerColumn
+	if resourceInfo.IsCustom {
+		for _, def := range c.printerColumns(ctx, resourceInfo) {
+			if def.Priority != 0 {
+				continue
+			}
+			parser := jsonpath.New(def.Name).AllowMissingKeys(true)
+			if err := parser.Parse(fmt.Sprintf("{%s}", def.JSONPath)); err != nil {
+				continue
+			}
+			columns = append(columns, printerColumn{
+				TableColumn: TableColumn{Name: strings.ToUpper(def.Name), Type: def.Type},
+				parser:      parser,
+			})
+		}
+	}
+
+	result := &ResourceTable{Columns: []TableColumn{{Name: "NAME", Type: "string"}}}
+	for _, col := range columns {
+		result.Columns = append(result.Columns, col.TableColumn)
+	}
+	if len(columns) == 0 {
+		result.Columns = append(result.Columns, TableColumn{Name: "AGE", Type: "date"})
+	}
+
+	for _, item := range items {
+		row := ResourceRow{
+			GVR:             resourceInfo.GVR,
+			Kind:            resourceInfo.GVK.Kind,
+			Namespace:       item.GetNamespace(),
+			Name:            item.GetName(),
+			UID:             item.GetUID(),
+			ResourceVersion: item.GetResourceVersion(),
+			Cells:           []string{item.GetName()},
+		}
+
+		for _, col := range columns {
+			var value any
+			if results, err := col.parser.FindResults(item.Object); err == nil && len(results) > 0 && len(results[0]) > 0 {
+				value = results[0][0].Interface()
+			}
+			row.Cells = append(row.Cells, formatCell(value, col.Type))
+		}
+		if len(columns) == 0 {
+			row.Cells = append(row.Cells, formatCell(item.GetCreationTimestamp().Format(time.RFC3339), "date"))
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
+
+func (c *UnifiedClient) printerColumns(ctx context.Context, resourceInfo *ResourceInfo) []apiextv1.CustomResourceColumnDefinition {
+	crdName := resourceInfo.GVR.Resource + "." + resourceInfo.GVR.Group
+	crd, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Name == resourceInfo.GVR.Version {
+			return version.AdditionalPrinterColumns
+		}
+	}
+	return nil
+}
+
+func formatCell(value any, columnType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if columnType == "date" {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return Age(t)
+			}
+		}
+		if v == "" {
+			return "<none>"
+		}
+		return v
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, part := range v {
+			parts = append(parts, formatCell(part, ""))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// API path for a resource collection
+func resourcePath(gvr schema.GroupVersionResource, namespace string) string {
+	segments := []string{"/apis", gvr.Group, gvr.Version}
+	if gvr.Group == "" {
+		segments = []string{"/api", gvr.Version}
+	}
+	if namespace != "" {
+		segments = append(segments, "namespaces", namespace)
+	}
+	segments = append(segments, gvr.Resource)
+	return path.Join(segments...)
+}
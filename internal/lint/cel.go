@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// A house rule as written in the config file. The expression sees the object
+// as `object` and must be true for objects that follow the rule, like the
+// validations of a ValidatingAdmissionPolicy.
+type CELRuleSpec struct {
+	ID         string   `yaml:"id"`
+	Severity   string   `yaml:"severity"` // "error", "warning" (the default) or "info"
+	Message    string   `yaml:"message"`
+	Kinds      []string `yaml:"kinds,omitempty"` // Kinds the rule checks, all if empty
+	Expression string   `yaml:"expression"`
+}
+
+// Rule evaluating a compiled CEL expression against whole objects
+type celRule struct {
+	spec     CELRuleSpec
+	severity Severity
+	program  cel.Program
+}
+
+// Compile rules from the config file, reporting every invalid rule at once
+func CompileCELRules(specs []CELRuleSpec) ([]Rule, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		ext.Strings(),
+		ext.Lists(),
+		ext.Sets(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	var errs []error
+	ids := make(map[string]bool)
+	for i, spec := range specs {
+		rule, err := compileCELRule(env, spec)
+		if err == nil && ids[spec.ID] {
+			err = errors.New("duplicate id")
+		}
+		if err != nil {
+			name := spec.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			errs = append(errs, fmt.Errorf("rule %s: %w", name, err))
+			continue
+		}
+		ids[spec.ID] = true
+		rules = append(rules, rule)
+	}
+	return rules, errors.Join(errs...)
+}
+
+func compileCELRule(env *cel.Env, spec CELRuleSpec) (*celRule, error) {
+	if spec.ID == "" {
+		return nil, errors.New("id is required")
+	}
+	if spec.Message == "" {
+		return nil, errors.New("message is required")
+	}
+	severity, err := ParseSeverity(spec.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(spec.Expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %s", ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &celRule{spec: spec, severity: severity, program: program}, nil
+}
+
+// Severity by name, warning if empty
+func ParseSeverity(name string) (Severity, error) {
+	switch name {
+	case "error":
+		return SeverityError, nil
+	case "warning", "":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	}
+	return SeverityInfo, fmt.Errorf("unknown severity %q, must be error, warning or info", name)
+}
+
+func (r *celRule) ID() string {
+	return r.spec.ID
+}
+
+// An expression failing to evaluate, e.g. on a missing field without has(),
+// counts as a violation so a broken rule doesn't pass silently
+func (r *celRule) Check(obj *unstructured.Unstructured, env *Environment) []Finding {
+	if len(r.spec.Kinds) > 0 && !slices.Contains(r.spec.Kinds, obj.GetKind()) {
+		return nil
+	}
+
+	message := r.spec.Message
+	result, _, err := r.program.Eval(map[string]any{"object": obj.Object})
+	switch {
+	case err != nil:
+		message = fmt.Sprintf("%s (evaluation failed: %v)", message, err)
+	case result.Value() == true:
+		return nil
+	case result.Value() != false:
+		message = fmt.Sprintf("%s (expression returned %v, not a bool)", message, result.Value())
+	}
+	return []Finding{{Rule: r.spec.ID, Severity: r.severity, Message: message}}
+}
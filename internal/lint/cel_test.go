@@ -0,0 +1,160 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileCELRules(t *testing.T) {
+	valid := CELRuleSpec{ID: "team-label", Message: "set a team label", Expression: `has(object.metadata.labels) && "team" in object.metadata.labels`}
+
+	tests := []struct {
+		name    string
+		specs   []CELRuleSpec
+		want    int      // Rules compiled
+		wantErr []string // Parts of the error, one per invalid rule
+	}{
+		{name: "none"},
+		{name: "valid", specs: []CELRuleSpec{valid}, want: 1},
+		{
+			name: "string extensions",
+			specs: []CELRuleSpec{{
+				ID: "lowercase", Message: "m", Severity: "info",
+				Expression: `object.metadata.name.lowerAscii() == object.metadata.name`,
+			}},
+			want: 1,
+		},
+		{
+			name: "every invalid rule is reported",
+			specs: []CELRuleSpec{
+				{Message: "m", Expression: "true"},
+				{ID: "no-message", Expression: "true"},
+				{ID: "severity", Message: "m", Severity: "fatal", Expression: "true"},
+				{ID: "syntax", Message: "m", Expression: "object.metadata.name =="},
+				{ID: "not-bool", Message: "m", Expression: `"yes"`},
+				valid,
+			},
+			want: 1,
+			wantErr: []string{
+				"rule #1: id is required",
+				"rule no-message: message is required",
+				`rule severity: unknown severity "fatal"`,
+				"rule syntax: ",
+				"rule not-bool: expression must evaluate to a bool, not string",
+			},
+		},
+		{
+			name:    "duplicate id",
+			specs:   []CELRuleSpec{valid, valid},
+			want:    1,
+			wantErr: []string{"rule team-label: duplicate id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := CompileCELRules(tt.specs)
+			if len(rules) != tt.want {
+				t.Errorf("compiled %d rules, want %d", len(rules), tt.want)
+			}
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("no error")
+			}
+			joined, ok := err.(interface{ Unwrap() []error })
+			if !ok || len(joined.Unwrap()) != len(tt.wantErr) {
+				t.Fatalf("error:\n%v\nwant %d errors", err, len(tt.wantErr))
+			}
+			for i, want := range tt.wantErr {
+				if got := joined.Unwrap()[i].Error(); !strings.Contains(got, want) {
+					t.Errorf("error %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCELRuleCheck(t *testing.T) {
+	deployment := `
+kind: Deployment
+metadata:
+  name: web
+  labels: {team: shop}
+spec:
+  replicas: 3
+`
+	configMap := `
+kind: ConfigMap
+metadata: {name: settings}
+`
+	tests := []struct {
+		name     string
+		spec     CELRuleSpec
+		manifest string
+		want     string // Message of the finding, empty for none
+	}{
+		{
+			name:     "passes",
+			spec:     CELRuleSpec{Expression: `object.metadata.labels.team != ""`},
+			manifest: deployment,
+		},
+		{
+			name:     "violated",
+			spec:     CELRuleSpec{Expression: `object.spec.replicas <= 2`},
+			manifest: deployment,
+			want:     "too many",
+		},
+		{
+			name:     "kind not checked",
+			spec:     CELRuleSpec{Kinds: []string{"StatefulSet", "Deployment"}, Expression: `object.spec.replicas <= 2`},
+			manifest: configMap,
+		},
+		{
+			name:     "kind checked",
+			spec:     CELRuleSpec{Kinds: []string{"StatefulSet", "Deployment"}, Expression: `object.spec.replicas <= 2`},
+			manifest: deployment,
+			want:     "too many",
+		},
+		{
+			// A missing field without has() fails the evaluation, which must not pass
+			name:     "evaluation fails",
+			spec:     CELRuleSpec{Expression: `object.metadata.labels.team != ""`},
+			manifest: configMap,
+			want:     "too many (evaluation failed: no such key: labels)",
+		},
+		{
+			name:     "not a bool at runtime",
+			spec:     CELRuleSpec{Expression: `object.metadata.name`},
+			manifest: configMap,
+			want:     "too many (expression returned settings, not a bool)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.ID, tt.spec.Message, tt.spec.Severity = "rule", "too many", "error"
+			rules, err := CompileCELRules([]CELRuleSpec{tt.spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			findings := rules[0].Check(parseObject(t, tt.manifest), nil)
+			if tt.want == "" {
+				if len(findings) > 0 {
+					t.Errorf("findings = %v, want none", findings)
+				}
+				return
+			}
+			if len(findings) != 1 {
+				t.Fatalf("findings = %v, want one", findings)
+			}
+			finding := findings[0]
+			if finding.Rule != "rule" || finding.Severity != SeverityError || finding.Message != tt.want {
+				t.Errorf("finding = %+v, want an error with %q", finding, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// How bad a finding is, higher is worse
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+// Problem a rule found in an object
+type Finding struct {
+	Rule     string // ID of the rule, e.g. "latest-tag"
+	Severity Severity
+	Path     string // Field path, e.g. "spec.template.spec.containers[0].image", empty for the object
+	Message  string
+}
+
+// What rules may look at besides the object itself. Fields left nil are
+// unknown, e.g. without a cluster, and rules needing them don't run.
+type Environment struct {
+	PodDisruptionBudgets []policyv1.PodDisruptionBudget // Of the object's namespace
+}
+
+// A check of objects
+type Rule interface {
+	ID() string
+	// Findings for obj, nil if the rule doesn't apply to it. env may be nil.
+	Check(obj *unstructured.Unstructured, env *Environment) []Finding
+}
+
+// Runs rules against objects
+type Linter struct {
+	rules []Rule
+}
+
+func NewLinter(rules ...Rule) *Linter {
+	return &Linter{rules: rules}
+}
+
+// Linter with the built-in best-practice rules
+func Default() *Linter {
+	return NewLinter(BuiltinRules()...)
+}
+
+// Findings of every rule, worst first, then by field path
+func (l *Linter) Lint(obj *unstructured.Unstructured, env *Environment) []Finding {
+	var findings []Finding
+	for _, rule := range l.rules {
+		findings = append(findings, rule.Check(obj, env)...)
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if a.Severity != b.Severity {
+			return int(b.Severity - a.Severity)
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return findings
+}
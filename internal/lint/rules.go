@@ -0,0 +1,261 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Rule checking the pod spec of workloads, objects of other kinds are skipped
+type workloadRule struct {
+	id    string
+	check func(w *Workload, env *Environment) []Finding
+}
+
+func (r workloadRule) ID() string {
+	return r.id
+}
+
+func (r workloadRule) Check(obj *unstructured.Unstructured, env *Environment) []Finding {
+	w := NewWorkload(obj)
+	if w == nil {
+		return nil
+	}
+	findings := r.check(w, env)
+	for i := range findings {
+		findings[i].Rule = r.id
+	}
+	return findings
+}
+
+// Best-practice rules for pod specs of Pods, Deployments, StatefulSets,
+// DaemonSets, ReplicaSets, Jobs and CronJobs
+func BuiltinRules() []Rule {
+	return []Rule{
+		workloadRule{id: "privileged", check: checkPrivileged},
+		workloadRule{id: "run-as-root", check: checkRunAsRoot},
+		workloadRule{id: "host-path", check: checkHostPath},
+		workloadRule{id: "no-resource-requests", check: checkResourceRequests},
+		workloadRule{id: "no-resource-limits", check: checkResourceLimits},
+		workloadRule{id: "latest-tag", check: checkLatestTag},
+		workloadRule{id: "no-readiness-probe", check: checkReadinessProbe},
+		workloadRule{id: "no-liveness-probe", check: checkLivenessProbe},
+		workloadRule{id: "single-replica", check: checkSingleReplica},
+		workloadRule{id: "no-pod-disruption-budget", check: checkPodDisruptionBudget},
+	}
+}
+
+func checkPrivileged(w *Workload, env *Environment) []Finding {
+	var findings []Finding
+	for _, c := range w.containers() {
+		if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     c.Path + ".securityContext.privileged",
+				Message:  fmt.Sprintf("container %s is privileged and has full access to the node", c.Name),
+			})
+		}
+	}
+	return findings
+}
+
+// Containers inherit runAsUser and runAsNonRoot from the pod security context
+func checkRunAsRoot(w *Workload, env *Environment) []Finding {
+	var podUser *int64
+	var podNonRoot *bool
+	if sc := w.Spec.SecurityContext; sc != nil {
+		podUser, podNonRoot = sc.RunAsUser, sc.RunAsNonRoot
+	}
+
+	var findings []Finding
+	for _, c := range w.containers() {
+		user, nonRoot := podUser, podNonRoot
+		if sc := c.SecurityContext; sc != nil {
+			if sc.RunAsUser != nil {
+				user = sc.RunAsUser
+			}
+			if sc.RunAsNonRoot != nil {
+				nonRoot = sc.RunAsNonRoot
+			}
+		}
+
+		switch {
+		case user != nil && *user == 0:
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     c.Path + ".securityContext.runAsUser",
+				Message:  fmt.Sprintf("container %s runs as root (runAsUser: 0)", c.Name),
+			})
+		case user == nil && (nonRoot == nil || !*nonRoot):
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     c.Path + ".securityContext",
+				Message:  fmt.Sprintf("container %s may run as root, set runAsNonRoot: true or a non-zero runAsUser", c.Name),
+			})
+		}
+	}
+	return findings
+}
+
+func checkHostPath(w *Workload, env *Environment) []Finding {
+	var findings []Finding
+	for i, volume := range w.Spec.Volumes {
+		if volume.HostPath != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     fmt.Sprintf("%s.volumes[%d].hostPath", w.SpecPath, i),
+				Message:  fmt.Sprintf("volume %s mounts %s from the node", volume.Name, volume.HostPath.Path),
+			})
+		}
+	}
+	return findings
+}
+
+func checkResourceRequests(w *Workload, env *Environment) []Finding {
+	var findings []Finding
+	for _, c := range w.containers() {
+		if missing := missingResources(c.Resources.Requests, corev1.ResourceCPU, corev1.ResourceMemory); len(missing) > 0 {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     c.Path + ".resources.requests",
+				Message:  fmt.Sprintf("container %s has no %s request, the scheduler can't place it reliably", c.Name, strings.Join(missing, " or ")),
+			})
+		}
+	}
+	return findings
+}
+
+// A CPU limit throttles more than it protects, so only memory is required
+func checkResourceLimits(w *Workload, env *Environment) []Finding {
+	var findings []Finding
+	for _, c := range w.containers() {
+		if missing := missingResources(c.Resources.Limits, corev1.ResourceMemory); len(missing) > 0 {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     c.Path + ".resources.limits",
+				Message:  fmt.Sprintf("container %s has no memory limit and can starve other pods on the node", c.Name),
+			})
+		}
+	}
+	return findings
+}
+
+func missingResources(resources corev1.ResourceList, names ...corev1.ResourceName) []string {
+	var missing []string
+	for _, name := range names {
+		if _, ok := resources[name]; !ok {
+			missing = append(missing, string(name))
+		}
+	}
+	return missing
+}
+
+// Images without a tag or digest get latest as well
+func checkLatestTag(w *Workload, env *Environment) []Finding {
+	var findings []Finding
+	for _, c := range w.containers() {
+		image := c.Image
+		if strings.Contains(image, "@") {
+			continue // Pinned by digest
+		}
+		tag := ""
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			tag = image[i+1:]
+		}
+		message := ""
+		switch tag {
+		case "":
+			message = fmt.Sprintf("container %s image %s has no tag and pulls latest", c.Name, image)
+		case "latest":
+			message = fmt.Sprintf("container %s uses the latest tag", c.Name)
+		default:
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Path:     c.Path + ".image",
+			Message:  message + ", pin a version so rollouts are reproducible",
+		})
+	}
+	return findings
+}
+
+func checkReadinessProbe(w *Workload, env *Environment) []Finding {
+	if w.runsToCompletion() {
+		return nil
+	}
+	var findings []Finding
+	for _, c := range w.containers() {
+		if !c.Init && c.ReadinessProbe == nil {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     c.Path + ".readinessProbe",
+				Message:  fmt.Sprintf("container %s has no readiness probe, it gets traffic before it is ready", c.Name),
+			})
+		}
+	}
+	return findings
+}
+
+func checkLivenessProbe(w *Workload, env *Environment) []Finding {
+	if w.runsToCompletion() {
+		return nil
+	}
+	var findings []Finding
+	for _, c := range w.containers() {
+		if !c.Init && c.LivenessProbe == nil {
+			findings = append(findings, Finding{
+				Severity: SeverityInfo,
+				Path:     c.Path + ".livenessProbe",
+				Message:  fmt.Sprintf("container %s has no liveness probe, a hung process isn't restarted", c.Name),
+			})
+		}
+	}
+	return findings
+}
+
+// Kinds whose pods are replicas of each other
+var replicatedKinds = map[string]bool{"Deployment": true, "StatefulSet": true, "ReplicaSet": true}
+
+func checkSingleReplica(w *Workload, env *Environment) []Finding {
+	if !replicatedKinds[w.Object.GetKind()] {
+		return nil
+	}
+	replicas, found, _ := unstructured.NestedInt64(w.Object.Object, "spec", "replicas")
+	if !found {
+		replicas = 1 // The API server's default
+	}
+	if replicas != 1 {
+		return nil
+	}
+	return []Finding{{
+		Severity: SeverityWarning,
+		Path:     "spec.replicas",
+		Message:  "a single replica is unavailable during every rollout, node drain or crash",
+	}}
+}
+
+func checkPodDisruptionBudget(w *Workload, env *Environment) []Finding {
+	if env == nil || env.PodDisruptionBudgets == nil || !replicatedKinds[w.Object.GetKind()] {
+		return nil
+	}
+
+	podLabels := labels.Set(w.PodLabels)
+	for _, pdb := range env.PodDisruptionBudgets {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err == nil && !selector.Empty() && selector.Matches(podLabels) {
+			return nil
+		}
+	}
+	return []Finding{{
+		Severity: SeverityWarning,
+		Message:  "no PodDisruptionBudget covers the pods, a node drain may evict all of them at once",
+	}}
+}
@@ -0,0 +1,305 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Decode like the validator does, integers as int64
+func parseObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := utilyaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func parseWorkload(t *testing.T, manifest string) *Workload {
+	t.Helper()
+	w := NewWorkload(parseObject(t, manifest))
+	if w == nil {
+		t.Fatal("not a workload")
+	}
+	return w
+}
+
+// Paths of the findings, "-" for findings about the whole object
+func findingPaths(findings []Finding) []string {
+	var paths []string
+	for _, finding := range findings {
+		path := finding.Path
+		if path == "" {
+			path = "-"
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestCheckLatestTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{"nginx", true},
+		{"nginx:latest", true},
+		{"nginx:1.27", false},
+		{"registry.example.com:5000/team/app", true}, // The port isn't a tag
+		{"registry.example.com:5000/team/app:latest", true},
+		{"registry.example.com:5000/team/app:v2", false},
+		{"nginx@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", false},
+		{"nginx:latest@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", false}, // The digest wins
+	}
+	for _, tt := range tests {
+		w := parseWorkload(t, `
+kind: Pod
+spec:
+  containers:
+  - name: app
+    image: `+tt.image)
+		findings := checkLatestTag(w, nil)
+		if got := len(findings) > 0; got != tt.want {
+			t.Errorf("checkLatestTag(%q) = %v, want a finding: %v", tt.image, findings, tt.want)
+		}
+		if len(findings) > 0 && findings[0].Path != "spec.containers[0].image" {
+			t.Errorf("path = %q", findings[0].Path)
+		}
+	}
+}
+
+func TestCheckRunAsRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string // Paths of the findings
+		severity Severity
+	}{
+		{
+			name: "no security context",
+			manifest: `
+kind: Pod
+spec:
+  containers:
+  - {name: app, image: app:1}`,
+			want:     []string{"spec.containers[0].securityContext"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "pod runs as non-root",
+			manifest: `
+kind: Pod
+spec:
+  securityContext: {runAsNonRoot: true}
+  initContainers:
+  - {name: init, image: app:1}
+  containers:
+  - {name: app, image: app:1}`,
+		},
+		{
+			name: "pod runs as a user",
+			manifest: `
+kind: Deployment
+spec:
+  template:
+    spec:
+      securityContext: {runAsUser: 1000}
+      containers:
+      - {name: app, image: app:1}`,
+		},
+		{
+			name: "container overrides the pod's user with root",
+			manifest: `
+kind: Pod
+spec:
+  securityContext: {runAsUser: 1000}
+  containers:
+  - {name: app, image: app:1}
+  - name: debug
+    image: app:1
+    securityContext: {runAsUser: 0}`,
+			want:     []string{"spec.containers[1].securityContext.runAsUser"},
+			severity: SeverityError,
+		},
+		{
+			name: "pod runs as root",
+			manifest: `
+kind: Pod
+spec:
+  securityContext: {runAsUser: 0, runAsNonRoot: true}
+  containers:
+  - {name: app, image: app:1}`,
+			want:     []string{"spec.containers[0].securityContext.runAsUser"},
+			severity: SeverityError,
+		},
+		{
+			name: "container opts out of the pod's non-root",
+			manifest: `
+kind: CronJob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          securityContext: {runAsNonRoot: true}
+          containers:
+          - name: app
+            image: app:1
+            securityContext: {runAsNonRoot: false}`,
+			want:     []string{"spec.jobTemplate.spec.template.spec.containers[0].securityContext"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "container sets what the pod doesn't",
+			manifest: `
+kind: Pod
+spec:
+  containers:
+  - name: app
+    image: app:1
+    securityContext: {runAsNonRoot: true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkRunAsRoot(parseWorkload(t, tt.manifest), nil)
+			if got := findingPaths(findings); !slices.Equal(got, tt.want) {
+				t.Fatalf("findings = %q, want %q", got, tt.want)
+			}
+			for _, finding := range findings {
+				if finding.Severity != tt.severity {
+					t.Errorf("severity = %s, want %s", finding.Severity, tt.severity)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPodDisruptionBudget(t *testing.T) {
+	deployment := `
+kind: Deployment
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels: {app: web, tier: frontend}
+    spec:
+      containers:
+      - {name: web, image: web:1}`
+	pdb := func(selector *metav1.LabelSelector) policyv1.PodDisruptionBudget {
+		return policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector}}
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		env      *Environment
+		want     bool
+	}{
+		{name: "no environment", manifest: deployment, env: nil},
+		{name: "budgets unknown", manifest: deployment, env: &Environment{}}, // Nil, e.g. without a cluster
+		{name: "no budgets", manifest: deployment, env: &Environment{PodDisruptionBudgets: []policyv1.PodDisruptionBudget{}}, want: true},
+		{
+			name:     "matching budget",
+			manifest: deployment,
+			env: &Environment{PodDisruptionBudgets: []policyv1.PodDisruptionBudget{
+				pdb(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}),
+				pdb(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}),
+			}},
+		},
+		{
+			name:     "budget for other pods",
+			manifest: deployment,
+			env: &Environment{PodDisruptionBudgets: []policyv1.PodDisruptionBudget{
+				pdb(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "web", "tier": "backend"}}),
+			}},
+			want: true,
+		},
+		{
+			// An empty selector doesn't count, whatever policy/v1 makes of it
+			name:     "budgets without selectors",
+			manifest: deployment,
+			env: &Environment{PodDisruptionBudgets: []policyv1.PodDisruptionBudget{
+				pdb(nil),
+				pdb(&metav1.LabelSelector{}),
+			}},
+			want: true,
+		},
+		{
+			name: "not replicated",
+			manifest: `
+kind: DaemonSet
+spec:
+  template:
+    spec:
+      containers:
+      - {name: agent, image: agent:1}`,
+			env: &Environment{PodDisruptionBudgets: []policyv1.PodDisruptionBudget{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkPodDisruptionBudget(parseWorkload(t, tt.manifest), tt.env)
+			if got := len(findings) > 0; got != tt.want {
+				t.Errorf("findings = %v, want a finding: %v", findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSingleReplica(t *testing.T) {
+	tests := []struct {
+		manifest string
+		want     bool
+	}{
+		{"kind: Deployment\nspec:\n  template:\n    spec:\n      containers: [{name: a, image: a:1}]", true}, // Defaults to 1
+		{"kind: Deployment\nspec:\n  replicas: 1\n  template:\n    spec:\n      containers: [{name: a, image: a:1}]", true},
+		{"kind: StatefulSet\nspec:\n  replicas: 2\n  template:\n    spec:\n      containers: [{name: a, image: a:1}]", false},
+		{"kind: Job\nspec:\n  template:\n    spec:\n      containers: [{name: a, image: a:1}]", false},
+	}
+	for _, tt := range tests {
+		if got := len(checkSingleReplica(parseWorkload(t, tt.manifest), nil)) > 0; got != tt.want {
+			t.Errorf("checkSingleReplica(%q) = %v, want %v", tt.manifest, got, tt.want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	obj := parseObject(t, `
+kind: Job
+metadata: {name: migrate}
+spec:
+  template:
+    spec:
+      securityContext: {runAsNonRoot: true}
+      volumes:
+      - name: host
+        hostPath: {path: /var/run}
+      containers:
+      - name: migrate
+        image: migrate:latest
+        securityContext: {privileged: true}
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {memory: 64Mi}
+`)
+
+	var got []string
+	for _, finding := range Default().Lint(obj, nil) {
+		got = append(got, finding.Rule)
+	}
+	// Worst first, then by path. Jobs run to completion and need no probes.
+	want := []string{"privileged", "latest-tag", "host-path"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rules = %q, want %q", got, want)
+	}
+
+	if findings := Default().Lint(parseObject(t, "kind: ConfigMap\nmetadata: {name: settings}"), nil); findings != nil {
+		t.Errorf("findings for a ConfigMap: %v", findings)
+	}
+}
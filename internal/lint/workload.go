@@ -0,0 +1,96 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Field path of the pod template of each workload kind
+var podTemplatePaths = map[string][]string{
+	"Deployment":  {"spec", "template"},
+	"StatefulSet": {"spec", "template"},
+	"DaemonSet":   {"spec", "template"},
+	"ReplicaSet":  {"spec", "template"},
+	"Job":         {"spec", "template"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+}
+
+// An object running pods, with the spec of the pods it runs
+type Workload struct {
+	Object    *unstructured.Unstructured
+	Spec      corev1.PodSpec
+	SpecPath  string            // Field path of Spec, e.g. "spec.template.spec"
+	PodLabels map[string]string // Labels of the pods
+}
+
+// Whether obj is a kind that runs pods from a pod spec
+func IsWorkload(obj *unstructured.Unstructured) bool {
+	_, ok := podTemplatePaths[obj.GetKind()]
+	return ok || obj.GetKind() == "Pod"
+}
+
+// The workload of obj, nil if it isn't one or its pod spec can't be read
+func NewWorkload(obj *unstructured.Unstructured) *Workload {
+	w := &Workload{Object: obj}
+
+	var spec map[string]any
+	if obj.GetKind() == "Pod" {
+		spec, _, _ = unstructured.NestedMap(obj.Object, "spec")
+		w.SpecPath = "spec"
+		w.PodLabels = obj.GetLabels()
+	} else {
+		templatePath, ok := podTemplatePaths[obj.GetKind()]
+		if !ok {
+			return nil
+		}
+		spec, _, _ = unstructured.NestedMap(obj.Object, append(templatePath, "spec")...)
+		w.PodLabels, _, _ = unstructured.NestedStringMap(obj.Object, append(templatePath, "metadata", "labels")...)
+		w.SpecPath = strings.Join(templatePath, ".") + ".spec"
+	}
+
+	if spec == nil || runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &w.Spec) != nil {
+		return nil
+	}
+	return w
+}
+
+// A container of the pod spec with its field path
+type workloadContainer struct {
+	*corev1.Container
+	Path string
+	Init bool
+}
+
+// Init containers, then the regular ones
+func (w *Workload) containers() []workloadContainer {
+	var containers []workloadContainer
+	for i := range w.Spec.InitContainers {
+		containers = append(containers, workloadContainer{
+			Container: &w.Spec.InitContainers[i],
+			Path:      fmt.Sprintf("%s.initContainers[%d]", w.SpecPath, i),
+			Init:      true,
+		})
+	}
+	for i := range w.Spec.Containers {
+		containers = append(containers, workloadContainer{
+			Container: &w.Spec.Containers[i],
+			Path:      fmt.Sprintf("%s.containers[%d]", w.SpecPath, i),
+		})
+	}
+	return containers
+}
+
+// Whether the pods run to completion, so probes and replicas don't apply
+func (w *Workload) runsToCompletion() bool {
+	switch w.Object.GetKind() {
+	case "Job", "CronJob":
+		return true
+	case "Pod":
+		return w.Spec.RestartPolicy == corev1.RestartPolicyNever || w.Spec.RestartPolicy == corev1.RestartPolicyOnFailure
+	}
+	return false
+}
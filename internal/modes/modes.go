@@ -6,4 +6,12 @@ const (
 	Welcome         Mode = "welcome"
 	Explorer        Mode = "explorer"
 	ResourceDetails Mode = "resourcedetails"
+	Logs            Mode = "logs"
+	PortForwards    Mode = "portforwards"
+	Events          Mode = "events"
+	Apply           Mode = "apply"
+	Editor          Mode = "editor"
+	Deprecations    Mode = "deprecations"
+	AIAnalysis      Mode = "aianalysis"
+	AIChat          Mode = "aichat"
 )
\ No newline at end of file
@@ -66,6 +66,28 @@ func GetDefaultKeyBindings() *KeyBindings {
 		{Rune: '?', Description: "Show help", Mode: modes.Welcome},
 		{Rune: '?', Description: "Show help", Mode: modes.Explorer},
 		{Rune: '?', Description: "Show help", Mode: modes.ResourceDetails},
+		{Key: tcell.KeyEsc, Description: "Go back/Exit", Mode: modes.Logs},
+		{Rune: 'q', Description: "Quit application", Mode: modes.Logs},
+		{Rune: '?', Description: "Show help", Mode: modes.Logs},
+		{Key: tcell.KeyEsc, Description: "Go back/Exit", Mode: modes.PortForwards},
+		{Rune: 'q', Description: "Quit application", Mode: modes.PortForwards},
+		{Rune: '?', Description: "Show help", Mode: modes.PortForwards},
+		{Key: tcell.KeyEsc, Description: "Go back/Exit", Mode: modes.Events},
+		{Rune: 'q', Description: "Quit application", Mode: modes.Events},
+		{Rune: '?', Description: "Show help", Mode: modes.Events},
+		{Key: tcell.KeyEsc, Description: "Abort all and go back", Mode: modes.Apply},
+		{Rune: 'q', Description: "Quit application", Mode: modes.Apply},
+		{Rune: '?', Description: "Show help", Mode: modes.Apply},
+		{Key: tcell.KeyEsc, Description: "Close editor", Mode: modes.Editor},
+		{Key: tcell.KeyF1, Description: "Show help", Mode: modes.Editor},
+		{Key: tcell.KeyEsc, Description: "Go back/Exit", Mode: modes.Deprecations},
+		{Rune: 'q', Description: "Quit application", Mode: modes.Deprecations},
+		{Rune: '?', Description: "Show help", Mode: modes.Deprecations},
+		{Key: tcell.KeyEsc, Description: "Stop and go back", Mode: modes.AIAnalysis},
+		{Rune: 'q', Description: "Quit application", Mode: modes.AIAnalysis},
+		{Rune: '?', Description: "Show help", Mode: modes.AIAnalysis},
+		{Key: tcell.KeyEsc, Description: "Close chat", Mode: modes.AIChat},
+		{Key: tcell.KeyF1, Description: "Show help", Mode: modes.AIChat},
 	}
 	
 	// Welcome mode specific bindings
@@ -75,17 +97,106 @@ func GetDefaultKeyBindings() *KeyBindings {
 	
 	// Explorer mode specific bindings
 	explorerBindings := []KeyBind{
+		{Rune: 'c', Description: "Switch context", Mode: modes.Explorer},
 		{Rune: 'n', Description: "Switch namespace", Mode: modes.Explorer},
 		{Rune: 'r', Description: "Switch resource type", Mode: modes.Explorer},
 		{Key: tcell.KeyEnter, Description: "View resource details", Mode: modes.Explorer},
+		{Rune: 'd', Description: "Toggle describe/YAML in details", Mode: modes.Explorer},
 		{Rune: 'j', Description: "Move down", Mode: modes.Explorer},
 		{Rune: 'k', Description: "Move up", Mode: modes.Explorer},
 		{Rune: 'a', Description: "AI analysis (failed pods)", Mode: modes.Explorer},
+		{Rune: 'l', Description: "View pod logs", Mode: modes.Explorer},
+		{Rune: 's', Description: "Shell into pod container", Mode: modes.Explorer},
+		{Rune: 'f', Description: "Port forward pod/service", Mode: modes.Explorer},
+		{Rune: 'F', Description: "List port forwards", Mode: modes.Explorer},
+		{Rune: 'E', Description: "View namespace events", Mode: modes.Explorer},
+		{Rune: 'A', Description: "Apply manifest file", Mode: modes.Explorer},
+		{Rune: 'm', Description: "Open manifest editor", Mode: modes.Explorer},
+		{Rune: 'e', Description: "Edit in $EDITOR", Mode: modes.Explorer},
+		{Rune: 'U', Description: "Deprecated API report", Mode: modes.Explorer},
+		{Rune: 'C', Description: "AI chat about resource", Mode: modes.Explorer},
+	}
+	
+	// Logs mode specific bindings
+	logsBindings := []KeyBind{
+		{Rune: 'f', Description: "Toggle follow", Mode: modes.Logs},
+		{Rune: 'p', Description: "Toggle previous container", Mode: modes.Logs},
+		{Rune: 't', Description: "Toggle timestamps", Mode: modes.Logs},
+		{Rune: 's', Description: "Cycle since time", Mode: modes.Logs},
+		{Rune: 'l', Description: "Cycle tail lines", Mode: modes.Logs},
+		{Rune: 'c', Description: "Pick container", Mode: modes.Logs},
+		{Rune: '/', Description: "Search", Mode: modes.Logs},
+		{Rune: 'n', Description: "Next match", Mode: modes.Logs},
+		{Rune: 'N', Description: "Previous match", Mode: modes.Logs},
+	}
+	
+	// Port forwards mode specific bindings
+	portForwardsBindings := []KeyBind{
+		{Rune: 'd', Description: "Stop port forward", Mode: modes.PortForwards},
+	}
+	
+	// Events mode specific bindings
+	eventsBindings := []KeyBind{
+		{Rune: 'j', Description: "Move down", Mode: modes.Events},
+		{Rune: 'k', Description: "Move up", Mode: modes.Events},
+		{Rune: 'R', Description: "Refresh events", Mode: modes.Events},
+	}
+	
+	// Apply mode specific bindings
+	applyBindings := []KeyBind{
+		{Rune: 'y', Description: "Apply changed objects", Mode: modes.Apply},
+		{Rune: 'f', Description: "Toggle force conflicts", Mode: modes.Apply},
+		{Rune: 'j', Description: "Next object", Mode: modes.Apply},
+		{Rune: 'k', Description: "Previous object", Mode: modes.Apply},
+	}
+	
+	// Editor mode specific bindings
+	editorBindings := []KeyBind{
+		{Key: tcell.KeyCtrlS, Description: "Save to file", Mode: modes.Editor},
+		{Key: tcell.KeyCtrlR, Description: "Preview and apply", Mode: modes.Editor},
+		{Key: tcell.KeyCtrlF, Description: "Search", Mode: modes.Editor},
+		{Key: tcell.KeyCtrlN, Description: "Next match", Mode: modes.Editor},
+		{Key: tcell.KeyCtrlZ, Description: "Undo", Mode: modes.Editor},
+		{Key: tcell.KeyCtrlY, Description: "Redo", Mode: modes.Editor},
+		{Key: tcell.KeyTab, Description: "Indent", Mode: modes.Editor},
+	}
+	
+	// Deprecations mode specific bindings
+	deprecationsBindings := []KeyBind{
+		{Rune: 'j', Description: "Move down", Mode: modes.Deprecations},
+		{Rune: 'k', Description: "Move up", Mode: modes.Deprecations},
+		{Rune: '[', Description: "Previous target version", Mode: modes.Deprecations},
+		{Rune: ']', Description: "Next target version", Mode: modes.Deprecations},
+		{Rune: 'f', Description: "Scan manifest file or directory", Mode: modes.Deprecations},
+		{Rune: 'R', Description: "Rescan", Mode: modes.Deprecations},
+	}
+	
+	// AI analysis mode specific bindings
+	aiAnalysisBindings := []KeyBind{
+		{Rune: 'x', Description: "Stop generating", Mode: modes.AIAnalysis},
+		{Rune: 'p', Description: "Show what was sent", Mode: modes.AIAnalysis},
+	}
+	
+	// AI chat mode specific bindings
+	aiChatBindings := []KeyBind{
+		{Key: tcell.KeyEnter, Description: "Ask question", Mode: modes.AIChat},
+		{Key: tcell.KeyTab, Description: "Switch between input and transcript", Mode: modes.AIChat},
+		{Key: tcell.KeyCtrlX, Description: "Stop answer", Mode: modes.AIChat},
+		{Key: tcell.KeyCtrlL, Description: "Clear conversation", Mode: modes.AIChat},
+		{Key: tcell.KeyCtrlS, Description: "Export transcript to markdown", Mode: modes.AIChat},
 	}
 	
 	// Add all bindings
 	allBindings := append(globalBindings, welcomeBindings...)
 	allBindings = append(allBindings, explorerBindings...)
+	allBindings = append(allBindings, logsBindings...)
+	allBindings = append(allBindings, portForwardsBindings...)
+	allBindings = append(allBindings, eventsBindings...)
+	allBindings = append(allBindings, applyBindings...)
+	allBindings = append(allBindings, editorBindings...)
+	allBindings = append(allBindings, deprecationsBindings...)
+	allBindings = append(allBindings, aiAnalysisBindings...)
+	allBindings = append(allBindings, aiChatBindings...)
 	
 	for _, binding := range allBindings {
 		kb.AddBinding(binding)
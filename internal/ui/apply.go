@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"kubeguide/internal/diff"
+	"kubeguide/internal/kubernetes"
+)
+
+// Unchanged lines shown around each change in a diff
+const diffContextLines = 3
+
+// Preview of a manifest apply: every object with what the dry run says will
+// happen to it, and the diff of the selected one
+type ApplyView struct {
+	source  string // Where the manifest came from, e.g. a file path
+	plan    []*kubernetes.ApplyPlanItem
+	results []string // Outcome of the real apply per object, empty until applied
+	force   bool
+	status  string
+
+	onApply func()
+	onForce func(force bool)
+
+	list      *tview.Table
+	diffView  *tview.TextView
+	statusBar *tview.TextView
+}
+
+// onApply is called when the user asks to apply, onForce when forcing
+// conflicts is toggled and the plan has to be recomputed
+func NewApplyView(source string, onApply func(), onForce func(force bool)) *ApplyView {
+	return &ApplyView{
+		source:  source,
+		onApply: onApply,
+		onForce: onForce,
+		status:  "running server dry run...",
+	}
+}
+
+func (v *ApplyView) CreateView() tview.Primitive {
+	v.list = tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	v.list.SetBackgroundColor(tcell.ColorBlack)
+	v.list.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitle(fmt.Sprintf(" Apply: %s ", v.source)).
+		SetTitleColor(tcell.ColorWhite)
+	v.list.SetSelectionChangedFunc(func(row, column int) {
+		v.showDiff(row - 1)
+	})
+
+	v.diffView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetScrollable(true)
+	v.diffView.SetBackgroundColor(tcell.ColorBlack)
+	v.diffView.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitle(" Live vs. dry run ").
+		SetTitleColor(tcell.ColorWhite)
+
+	v.statusBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextColor(tcell.ColorLightGray)
+	v.statusBar.SetBackgroundColor(tcell.ColorBlack)
+
+	v.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'y':
+			if v.Pending() > 0 {
+				v.onApply()
+			}
+		case 'f':
+			v.onForce(!v.force)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	v.render()
+
+	return tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(v.list, 0, 1, true).
+			AddItem(v.diffView, 0, 2, false), 0, 1, true).
+		AddItem(v.statusBar, 1, 0, false)
+}
+
+// Show a new plan, e.g. after forcing conflicts was toggled
+func (v *ApplyView) SetPlan(plan []*kubernetes.ApplyPlanItem, force bool) {
+	v.plan = plan
+	v.results = make([]string, len(plan))
+	v.force = force
+	v.status = ""
+	v.render()
+}
+
+// Record the outcome of applying one object
+func (v *ApplyView) SetResult(index int, err error) {
+	v.results[index] = "[green]applied[-]"
+	if err != nil {
+		v.results[index] = fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error()))
+	}
+	v.render()
+}
+
+func (v *ApplyView) SetStatus(status string) {
+	v.status = status
+	v.render()
+}
+
+// Objects the apply would create or change
+func (v *ApplyView) Pending() int {
+	count := 0
+	for i, item := range v.plan {
+		if v.results[i] == "" && (item.Action == kubernetes.ApplyCreate || item.Action == kubernetes.ApplyUpdate) {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *ApplyView) render() {
+	selected, _ := v.list.GetSelection()
+	v.list.Clear()
+
+	for col, name := range []string{"ACTION", "KIND", "NAME", "RESULT"} {
+		v.list.SetCell(0, col, headerCell(name))
+	}
+	for i, item := range v.plan {
+		name := item.Object.GetName()
+		if item.Namespace != "" {
+			name = item.Namespace + "/" + name
+		}
+		cells := []string{actionText(item.Action), item.Object.GetKind(), name, v.results[i]}
+		for col, text := range cells {
+			v.list.SetCell(i+1, col, tview.NewTableCell(text).SetTextColor(tcell.ColorWhite))
+		}
+	}
+	if len(v.plan) > 0 {
+		v.list.Select(max(1, min(selected, len(v.plan))), 0)
+	}
+
+	counts := make(map[kubernetes.ApplyAction]int)
+	for _, item := range v.plan {
+		counts[item.Action]++
+	}
+	summary := fmt.Sprintf(" %d to create, %d to update, %d unchanged, %d failed | apply(y) force conflicts(f):%s abort all(Esc)",
+		counts[kubernetes.ApplyCreate], counts[kubernetes.ApplyUpdate], counts[kubernetes.ApplyUnchanged], counts[kubernetes.ApplyFailed], onOff(v.force))
+	if v.status != "" {
+		summary = " " + v.status + " |" + summary
+	}
+	v.statusBar.SetText(summary)
+
+	row, _ := v.list.GetSelection()
+	v.showDiff(row - 1)
+}
+
+func (v *ApplyView) showDiff(index int) {
+	v.diffView.Clear()
+	if index < 0 || index >= len(v.plan) {
+		return
+	}
+	item := v.plan[index]
+	v.diffView.SetTitle(fmt.Sprintf(" %s: live vs. dry run ", item))
+
+	if item.Err != nil {
+		fmt.Fprintf(v.diffView, "[red]%s[-]\n", tview.Escape(item.Err.Error()))
+
+		var conflictErr *kubernetes.ApplyConflictError
+		if errors.As(item.Err, &conflictErr) {
+			fmt.Fprintf(v.diffView, "\nFields owned by other managers:\n\n")
+			for _, conflict := range conflictErr.Conflicts {
+				fmt.Fprintf(v.diffView, "  [yellow]%s[-]  owned by %s\n", tview.Escape(conflict.Field), tview.Escape(conflict.Manager))
+			}
+			fmt.Fprintf(v.diffView, "\nPress 'f' to force the apply and take ownership of these fields.\n")
+		} else if hint := kubernetes.ErrorHint(item.Err); hint != "" {
+			fmt.Fprintf(v.diffView, "\n%s\n", hint)
+		}
+		v.diffView.ScrollToBeginning()
+		return
+	}
+
+	fmt.Fprint(v.diffView, RenderDiff(objectYAML(item.Live), objectYAML(item.DryRun)))
+	v.diffView.ScrollToBeginning()
+}
+
+// Colourised unified diff of two YAML documents
+func RenderDiff(before, after string) string {
+	lines := diff.Lines(before, after)
+	if !diff.Changed(lines) {
+		return "[gray]No changes[-]\n"
+	}
+
+	var text strings.Builder
+	for _, line := range diff.Unified(lines, diffContextLines) {
+		escaped := tview.Escape(line.Text)
+		switch line.Op {
+		case diff.Insert:
+			fmt.Fprintf(&text, "[green]+ %s[-]\n", escaped)
+		case diff.Delete:
+			fmt.Fprintf(&text, "[red]- %s[-]\n", escaped)
+		case diff.Skip:
+			fmt.Fprintf(&text, "[darkcyan]@@ %s @@[-]\n", escaped)
+		default:
+			fmt.Fprintf(&text, "  %s\n", escaped)
+		}
+	}
+	return text.String()
+}
+
+// YAML of an object without server-maintained metadata, which the plan
+// ignores when comparing, empty for nil
+func objectYAML(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return ""
+	}
+	cleaned := kubernetes.ComparableData(*obj.DeepCopy())
+	data, err := yaml.Marshal(cleaned.Object)
+	if err != nil {
+		return fmt.Sprintf("# failed to render object: %v\n", err)
+	}
+	return string(data)
+}
+
+func actionText(action kubernetes.ApplyAction) string {
+	switch action {
+	case kubernetes.ApplyCreate:
+		return "[green]create[-]"
+	case kubernetes.ApplyUpdate:
+		return "[yellow]update[-]"
+	case kubernetes.ApplyFailed:
+		return "[red]error[-]"
+	default:
+		return "[gray]" + string(action) + "[-]"
+	}
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/ai"
+)
+
+// Transcript of an AI chat above a question input. Enter asks, Tab moves
+// between the input and the transcript for scrolling.
+type ChatView struct {
+	app        *tview.Application
+	resource   string
+	transcript *tview.TextView
+	input      *tview.InputField
+	layout     *tview.Flex
+}
+
+func NewChatView(app *tview.Application, resource string, onAsk func(question string)) *ChatView {
+	v := &ChatView{app: app, resource: resource}
+
+	v.transcript = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true).
+		SetScrollable(true)
+	v.transcript.SetBackgroundColor(tcell.ColorBlack)
+	v.transcript.SetTextColor(tcell.ColorWhite)
+	v.transcript.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitleColor(tcell.ColorWhite)
+
+	v.input = tview.NewInputField().
+		SetLabel("Ask: ").
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabelColor(tcell.ColorLightBlue)
+	v.input.SetBorder(true).SetBorderColor(tcell.ColorLightBlue)
+	v.input.SetDoneFunc(func(key tcell.Key) {
+		question := strings.TrimSpace(v.input.GetText())
+		if key != tcell.KeyEnter || question == "" {
+			return
+		}
+		v.input.SetText("")
+		onAsk(question)
+	})
+
+	v.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.transcript, 0, 1, false).
+		AddItem(v.input, 3, 0, true)
+	v.layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyTab {
+			return event
+		}
+		if v.input.HasFocus() {
+			v.app.SetFocus(v.transcript)
+		} else {
+			v.app.SetFocus(v.input)
+		}
+		return nil
+	})
+
+	v.SetStatus("")
+	return v
+}
+
+func (v *ChatView) Primitive() tview.Primitive {
+	return v.layout
+}
+
+func (v *ChatView) HasFocus() bool {
+	return v.layout.HasFocus()
+}
+
+// Put a question back into the input, e.g. one that wasn't sent
+func (v *ChatView) SetQuestion(question string) {
+	v.input.SetText(question)
+}
+
+// Show the keys and what the chat is doing, e.g. "Answering..."
+func (v *ChatView) SetStatus(status string) {
+	title := fmt.Sprintf(" AI Chat: %s (Enter to ask, Ctrl-X to stop, Ctrl-L to clear, Ctrl-S to export, Esc to return) ", v.resource)
+	if status != "" {
+		title = fmt.Sprintf(" AI Chat: %s - %s ", v.resource, status)
+	}
+	v.transcript.SetTitle(title)
+}
+
+// Render the conversation, with the question being answered and the answer
+// so far if one is in flight
+func (v *ChatView) Render(messages []ai.ChatMessage, question, partial string) {
+	var b strings.Builder
+	if len(messages) == 0 && question == "" {
+		b.WriteString("[gray]Ask anything about this resource, e.g. \"why would the probe fail?\". The resource YAML is sent along with every question.[-]\n")
+	}
+	for _, message := range messages {
+		writeChatMessage(&b, message)
+	}
+	if question != "" {
+		writeChatMessage(&b, ai.ChatMessage{Role: "user", Content: question})
+		writeChatMessage(&b, ai.ChatMessage{Role: "assistant", Content: partial})
+	}
+	v.transcript.SetText(b.String())
+	v.transcript.ScrollToEnd()
+}
+
+func writeChatMessage(b *strings.Builder, message ai.ChatMessage) {
+	if message.Role == "user" {
+		b.WriteString("[lightblue::b]You[-::-]\n")
+	} else {
+		b.WriteString("[green::b]Assistant[-::-]\n")
+	}
+	b.WriteString(tview.Escape(strings.TrimSpace(message.Content)))
+	b.WriteString("\n\n")
+}
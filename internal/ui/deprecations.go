@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Render deprecated API findings, removed APIs in red and merely deprecated
+// ones in yellow. err is shown above the findings, e.g. for kinds that could
+// not be listed.
+func RenderDeprecations(table *tview.Table, findings []kubernetes.DeprecationFinding, err error) {
+	table.Clear()
+
+	columns := []string{"STATUS", "OBJECT", "API VERSION", "REPLACEMENT", "DEPRECATED", "REMOVED", "SOURCE", "NOTE"}
+	for col, name := range columns {
+		table.SetCell(0, col, headerCell(name))
+	}
+
+	row := 1
+	if err != nil {
+		table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("Error scanning: %v", err)).
+			SetTextColor(tcell.ColorRed).
+			SetSelectable(false))
+		row++
+	}
+	if len(findings) == 0 {
+		table.SetCell(row, 0, tview.NewTableCell("No deprecated APIs found").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+
+	firstFinding := row // Below the error, which can't be selected
+	for _, finding := range findings {
+		status, color := "DEPRECATED", tcell.ColorYellow
+		if finding.Removed {
+			status, color = "REMOVED", tcell.ColorRed
+		}
+		replacement := finding.API.Replacement
+		if replacement == "" {
+			replacement = "<none>"
+		}
+
+		cells := []string{
+			status, finding.Object, finding.API.APIVersion, replacement,
+			finding.API.DeprecatedIn, finding.API.RemovedIn, finding.Source, finding.API.Note,
+		}
+		for col, text := range cells {
+			table.SetCell(row, col, tview.NewTableCell(text).SetTextColor(color))
+		}
+		row++
+	}
+	table.Select(firstFinding, 0).ScrollToBeginning()
+}
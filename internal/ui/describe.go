@@ -0,0 +1,478 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Annotations too noisy to show in the summary
+var hiddenAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// Indented "Key: value" lines, aligned per block like kubectl describe
+type describeWriter struct {
+	out *tabwriter.Writer
+}
+
+func (w *describeWriter) line(level int, format string, args ...any) {
+	fmt.Fprintf(w.out, strings.Repeat("  ", level)+format+"\n", args...)
+}
+
+// Summarize an object the way kubectl describe does. Pods, deployments,
+// other workload controllers and services get kind specific sections, any
+// other kind shows its metadata and status conditions.
+func Describe(obj *unstructured.Unstructured, related *kubernetes.RelatedObjects) string {
+	var text strings.Builder
+	w := &describeWriter{out: tabwriter.NewWriter(&text, 0, 8, 2, ' ', 0)}
+	if related == nil {
+		related = &kubernetes.RelatedObjects{}
+	}
+
+	describeMetadata(w, obj)
+
+	group := obj.GroupVersionKind().Group
+	var err error
+	switch {
+	case group == "" && obj.GetKind() == "Pod":
+		err = describeTyped(obj, func(pod *corev1.Pod) { describePod(w, pod) })
+	case group == "" && obj.GetKind() == "Service":
+		err = describeTyped(obj, func(service *corev1.Service) { describeService(w, service, related) })
+	case group == "apps" && obj.GetKind() == "Deployment":
+		err = describeTyped(obj, func(deployment *appsv1.Deployment) { describeDeployment(w, deployment) })
+	case group == "apps" && obj.GetKind() == "StatefulSet":
+		err = describeTyped(obj, func(statefulSet *appsv1.StatefulSet) { describeStatefulSet(w, statefulSet) })
+	case group == "apps" && obj.GetKind() == "DaemonSet":
+		err = describeTyped(obj, func(daemonSet *appsv1.DaemonSet) { describeDaemonSet(w, daemonSet) })
+	case group == "apps" && obj.GetKind() == "ReplicaSet":
+		err = describeTyped(obj, func(replicaSet *appsv1.ReplicaSet) { describeReplicaSet(w, replicaSet) })
+	}
+	if err != nil {
+		w.line(0, "Error:\t%v", err)
+	}
+
+	describeConditions(w, obj)
+	if obj.GetKind() != "Service" {
+		describeSelectorPods(w, related.SelectorPods)
+	}
+
+	w.out.Flush()
+	return text.String()
+}
+
+func describeTyped[T any](obj *unstructured.Unstructured, describe func(*T)) error {
+	var typed T
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &typed); err != nil {
+		return fmt.Errorf("failed to convert %s: %w", obj.GetKind(), err)
+	}
+	describe(&typed)
+	return nil
+}
+
+func describeMetadata(w *describeWriter, obj *unstructured.Unstructured) {
+	w.line(0, "Name:\t%s", obj.GetName())
+	if obj.GetNamespace() != "" {
+		w.line(0, "Namespace:\t%s", obj.GetNamespace())
+	}
+	w.line(0, "Kind:\t%s (%s)", obj.GetKind(), obj.GetAPIVersion())
+	w.line(0, "Created:\t%s (%s ago)", obj.GetCreationTimestamp().Format(time.RFC3339), kubernetes.Age(obj.GetCreationTimestamp().Time))
+	if deleted := obj.GetDeletionTimestamp(); deleted != nil {
+		w.line(0, "Terminating:\tsince %s", deleted.Format(time.RFC3339))
+	}
+
+	describeMap(w, "Labels", obj.GetLabels(), nil)
+	describeMap(w, "Annotations", obj.GetAnnotations(), hiddenAnnotations)
+
+	owners := obj.GetOwnerReferences()
+	if len(owners) == 0 {
+		w.line(0, "Owner:\t<none>")
+	}
+	for i, owner := range owners {
+		label := ""
+		if i == 0 {
+			label = "Owner:"
+		}
+		controller := ""
+		if owner.Controller != nil && *owner.Controller {
+			controller = " (controller)"
+		}
+		w.line(0, "%s\t%s/%s%s", label, owner.Kind, owner.Name, controller)
+	}
+}
+
+func describeMap(w *describeWriter, title string, values map[string]string, hidden []string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !slices.Contains(hidden, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	if len(keys) == 0 {
+		w.line(0, "%s:\t<none>", title)
+		return
+	}
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = title + ":"
+		}
+		w.line(0, "%s\t%s=%s", label, key, truncate(values[key], 80))
+	}
+}
+
+// Status conditions, read generically so custom resources get them too
+func describeConditions(w *describeWriter, obj *unstructured.Unstructured) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found || len(conditions) == 0 {
+		return
+	}
+
+	w.line(0, "Conditions:")
+	w.line(1, "Type\tStatus\tReason\tMessage")
+	w.line(1, "----\t------\t------\t-------")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		field := func(name string) string {
+			value, _ := condition[name].(string)
+			return value
+		}
+		w.line(1, "%s\t%s\t%s\t%s", field("type"), field("status"), field("reason"), truncate(field("message"), 100))
+	}
+}
+
+func describeSelectorPods(w *describeWriter, pods []kubernetes.PodSummary) {
+	if len(pods) == 0 {
+		return
+	}
+	w.line(0, "Selected Pods:")
+	for _, pod := range pods {
+		w.line(1, "%s\t%s\t%s", pod.Name, pod.Phase, readyText(pod.Ready))
+	}
+}
+
+func describePod(w *describeWriter, pod *corev1.Pod) {
+	w.line(0, "Node:\t%s", orNone(pod.Spec.NodeName))
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = fmt.Sprintf("%s (%s)", status, pod.Status.Reason)
+	}
+	w.line(0, "Status:\t%s", status)
+	if pod.Status.Message != "" {
+		w.line(0, "Message:\t%s", pod.Status.Message)
+	}
+	w.line(0, "IP:\t%s", orNone(pod.Status.PodIP))
+	w.line(0, "QoS Class:\t%s", orNone(string(pod.Status.QOSClass)))
+	w.line(0, "Service Account:\t%s", orNone(pod.Spec.ServiceAccountName))
+
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, list := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range list {
+			statuses[status.Name] = status
+		}
+	}
+
+	if len(pod.Spec.InitContainers) > 0 {
+		w.line(0, "Init Containers:")
+		for _, container := range pod.Spec.InitContainers {
+			describeContainer(w, container, statuses)
+		}
+	}
+	w.line(0, "Containers:")
+	for _, container := range pod.Spec.Containers {
+		describeContainer(w, container, statuses)
+	}
+}
+
+func describeContainer(w *describeWriter, container corev1.Container, statuses map[string]corev1.ContainerStatus) {
+	w.line(1, "%s:", container.Name)
+	w.line(2, "Image:\t%s", container.Image)
+
+	var ports []string
+	for _, port := range container.Ports {
+		ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, orDefault(string(port.Protocol), "TCP")))
+	}
+	if len(ports) > 0 {
+		w.line(2, "Ports:\t%s", strings.Join(ports, ", "))
+	}
+
+	if status, ok := statuses[container.Name]; ok {
+		w.line(2, "State:\t%s", containerState(status.State))
+		if status.LastTerminationState.Terminated != nil {
+			w.line(2, "Last State:\t%s", containerState(status.LastTerminationState))
+		}
+		w.line(2, "Ready:\t%t", status.Ready)
+		w.line(2, "Restart Count:\t%d", status.RestartCount)
+	}
+
+	if requests := resourceList(container.Resources.Requests); requests != "" {
+		w.line(2, "Requests:\t%s", requests)
+	}
+	if limits := resourceList(container.Resources.Limits); limits != "" {
+		w.line(2, "Limits:\t%s", limits)
+	}
+
+	for _, probe := range []struct {
+		name  string
+		probe *corev1.Probe
+	}{
+		{"Liveness", container.LivenessProbe},
+		{"Readiness", container.ReadinessProbe},
+		{"Startup", container.StartupProbe},
+	} {
+		if probe.probe != nil {
+			w.line(2, "%s:\t%s", probe.name, describeProbe(probe.probe))
+		}
+	}
+
+	if len(container.VolumeMounts) == 0 {
+		w.line(2, "Mounts:\t<none>")
+	}
+	for i, mount := range container.VolumeMounts {
+		label := ""
+		if i == 0 {
+			label = "Mounts:"
+		}
+		mode := "rw"
+		if mount.ReadOnly {
+			mode = "ro"
+		}
+		path := mount.MountPath
+		if mount.SubPath != "" {
+			path = fmt.Sprintf("%s (path %q)", path, mount.SubPath)
+		}
+		w.line(2, "%s\t%s from %s (%s)", label, path, mount.Name, mode)
+	}
+}
+
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return fmt.Sprintf("Running since %s", state.Running.StartedAt.Format(time.RFC3339))
+	case state.Waiting != nil:
+		text := fmt.Sprintf("Waiting (%s)", orDefault(state.Waiting.Reason, "unknown reason"))
+		if state.Waiting.Message != "" {
+			text += ": " + truncate(state.Waiting.Message, 100)
+		}
+		return text
+	case state.Terminated != nil:
+		terminated := state.Terminated
+		text := fmt.Sprintf("Terminated (%s), exit code %d", orDefault(terminated.Reason, "unknown reason"), terminated.ExitCode)
+		if terminated.Signal != 0 {
+			text += fmt.Sprintf(", signal %d", terminated.Signal)
+		}
+		if !terminated.FinishedAt.IsZero() {
+			text += fmt.Sprintf(", finished %s ago", kubernetes.Age(terminated.FinishedAt.Time))
+		}
+		return text
+	default:
+		return "<unknown>"
+	}
+}
+
+func describeProbe(probe *corev1.Probe) string {
+	var action string
+	switch {
+	case probe.HTTPGet != nil:
+		scheme := strings.ToLower(orDefault(string(probe.HTTPGet.Scheme), "http"))
+		action = fmt.Sprintf("http-get %s://%s:%s%s", scheme, probe.HTTPGet.Host, probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		action = fmt.Sprintf("tcp-socket %s:%s", probe.TCPSocket.Host, probe.TCPSocket.Port.String())
+	case probe.GRPC != nil:
+		action = fmt.Sprintf("grpc <pod>:%d %s", probe.GRPC.Port, orDefault(ptrValue(probe.GRPC.Service), ""))
+	case probe.Exec != nil:
+		action = fmt.Sprintf("exec %v", probe.Exec.Command)
+	default:
+		action = "unknown"
+	}
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #success=%d #failure=%d", action,
+		probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.SuccessThreshold, probe.FailureThreshold)
+}
+
+func resourceList(resources corev1.ResourceList) string {
+	var parts []string
+	for name, quantity := range resources {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ", ")
+}
+
+func describeDeployment(w *describeWriter, deployment *appsv1.Deployment) {
+	status := deployment.Status
+	w.line(0, "Replicas:\t%d desired | %d updated | %d total | %d available | %d unavailable",
+		ptrValue(deployment.Spec.Replicas), status.UpdatedReplicas, status.Replicas, status.AvailableReplicas, status.UnavailableReplicas)
+	strategy := string(deployment.Spec.Strategy.Type)
+	if rollingUpdate := deployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+		strategy = fmt.Sprintf("%s (max unavailable %s, max surge %s)", strategy, intOrStringText(rollingUpdate.MaxUnavailable), intOrStringText(rollingUpdate.MaxSurge))
+	}
+	w.line(0, "Strategy:\t%s", strategy)
+	w.line(0, "Selector:\t%s", selectorText(deployment.Spec.Selector))
+	w.line(0, "Rollout Status:\t%s", DeploymentRolloutStatus(deployment))
+	describePodTemplate(w, deployment.Spec.Template)
+}
+
+// Rollout progress as reported by kubectl rollout status
+func DeploymentRolloutStatus(deployment *appsv1.Deployment) string {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return "Waiting for the deployment spec update to be observed"
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return fmt.Sprintf("Failed: exceeded its progress deadline (%s)", condition.Message)
+		}
+	}
+
+	desired := ptrValue(deployment.Spec.Replicas)
+	status := deployment.Status
+	switch {
+	case status.UpdatedReplicas < desired:
+		return fmt.Sprintf("Waiting: %d out of %d new replicas have been updated", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("Waiting: %d old replicas are pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return fmt.Sprintf("Waiting: %d of %d updated replicas are available", status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		return "Successfully rolled out"
+	}
+}
+
+func describeStatefulSet(w *describeWriter, statefulSet *appsv1.StatefulSet) {
+	status := statefulSet.Status
+	w.line(0, "Replicas:\t%d desired | %d current | %d updated | %d ready",
+		ptrValue(statefulSet.Spec.Replicas), status.CurrentReplicas, status.UpdatedReplicas, status.ReadyReplicas)
+	w.line(0, "Update Strategy:\t%s", statefulSet.Spec.UpdateStrategy.Type)
+	w.line(0, "Selector:\t%s", selectorText(statefulSet.Spec.Selector))
+	describePodTemplate(w, statefulSet.Spec.Template)
+}
+
+func describeDaemonSet(w *describeWriter, daemonSet *appsv1.DaemonSet) {
+	status := daemonSet.Status
+	w.line(0, "Nodes:\t%d desired | %d scheduled | %d updated | %d ready | %d misscheduled",
+		status.DesiredNumberScheduled, status.CurrentNumberScheduled, status.UpdatedNumberScheduled, status.NumberReady, status.NumberMisscheduled)
+	w.line(0, "Update Strategy:\t%s", daemonSet.Spec.UpdateStrategy.Type)
+	w.line(0, "Selector:\t%s", selectorText(daemonSet.Spec.Selector))
+	describePodTemplate(w, daemonSet.Spec.Template)
+}
+
+func describeReplicaSet(w *describeWriter, replicaSet *appsv1.ReplicaSet) {
+	status := replicaSet.Status
+	w.line(0, "Replicas:\t%d desired | %d current | %d ready | %d available",
+		ptrValue(replicaSet.Spec.Replicas), status.Replicas, status.ReadyReplicas, status.AvailableReplicas)
+	w.line(0, "Selector:\t%s", selectorText(replicaSet.Spec.Selector))
+	describePodTemplate(w, replicaSet.Spec.Template)
+}
+
+// Containers of a workload's pod template, without the pod status
+func describePodTemplate(w *describeWriter, template corev1.PodTemplateSpec) {
+	w.line(0, "Pod Template:")
+	w.line(1, "Service Account:\t%s", orNone(template.Spec.ServiceAccountName))
+	for _, container := range template.Spec.Containers {
+		w.line(1, "%s:", container.Name)
+		w.line(2, "Image:\t%s", container.Image)
+		for _, mount := range container.VolumeMounts {
+			w.line(2, "Mount:\t%s from %s", mount.MountPath, mount.Name)
+		}
+	}
+}
+
+func describeService(w *describeWriter, service *corev1.Service, related *kubernetes.RelatedObjects) {
+	w.line(0, "Type:\t%s", service.Spec.Type)
+	w.line(0, "Cluster IP:\t%s", orNone(service.Spec.ClusterIP))
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		w.line(0, "External Name:\t%s", service.Spec.ExternalName)
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		w.line(0, "Load Balancer:\t%s", orDefault(ingress.IP, ingress.Hostname))
+	}
+
+	if len(service.Spec.Selector) == 0 {
+		w.line(0, "Selector:\t<none>")
+	} else {
+		w.line(0, "Selector:\t%s", metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector}))
+	}
+
+	w.line(0, "Ports:")
+	for _, port := range service.Spec.Ports {
+		text := fmt.Sprintf("%d/%s -> %s", port.Port, orDefault(string(port.Protocol), "TCP"), port.TargetPort.String())
+		if port.NodePort != 0 {
+			text += fmt.Sprintf(" (node port %d)", port.NodePort)
+		}
+		w.line(1, "%s\t%s", orDefault(port.Name, "<unnamed>"), text)
+	}
+
+	if len(related.Endpoints) == 0 {
+		w.line(0, "Endpoints:\t<none>")
+	} else {
+		w.line(0, "Endpoints:")
+		for _, endpoint := range related.Endpoints {
+			w.line(1, "%s\t%s\t%s", endpoint.Address, orNone(endpoint.Pod), readyText(endpoint.Ready))
+		}
+	}
+
+	describeSelectorPods(w, related.SelectorPods)
+}
+
+func selectorText(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return "<none>"
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+func intOrStringText(value *intstr.IntOrString) string {
+	if value == nil {
+		return "<unset>"
+	}
+	return value.String()
+}
+
+func readyText(ready bool) string {
+	if ready {
+		return "ready"
+	}
+	return "not ready"
+}
+
+func orNone(value string) string {
+	return orDefault(value, "<none>")
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func ptrValue[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(strings.ReplaceAll(text, "\n", " "))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	return string(runes[:length-3]) + "..."
+}
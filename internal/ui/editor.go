@@ -0,0 +1,393 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/validation"
+)
+
+// Indentation added for a nested YAML block. YAML forbids tabs, so Tab
+// inserts this as well.
+const editorIndent = "  "
+
+// Pause in typing after which the manifest is validated
+const editorValidationDelay = 500 * time.Millisecond
+
+// How long a validation may wait for schemas, e.g. from a slow cluster
+const editorValidationTimeout = 10 * time.Second
+
+// Multi-line YAML editor with line numbers, indentation-aware newlines,
+// undo/redo (Ctrl-Z/Ctrl-Y, built into the text area) and search
+type Editor struct {
+	app     *tview.Application
+	title   string // What is being edited, e.g. "new manifest" or "Deployment default/web"
+	path    string // File the manifest was last saved to, empty until saved
+	saved   string // Text as of opening or the last save, to detect unsaved changes
+	message string // Outcome of the last action, shown in the status bar
+	search  string
+
+	onSave  func()
+	onApply func()
+
+	// Live validation, nil when no schemas are available
+	validate             func(ctx context.Context, text string) []validation.Diagnostic
+	validationTimer      *time.Timer
+	validationCancel     context.CancelFunc // Cancels the validation in flight, if any
+	validationGeneration int                // Incremented on every change to drop results for older text
+
+	textArea       *numberedTextArea
+	frame          *tview.Flex
+	validationView *tview.TextView
+	statusBar      *tview.TextView
+	searchInput    *tview.InputField
+	layout         *tview.Flex
+}
+
+// onSave is called when the user asks to save (Ctrl-S), onApply when the
+// manifest should be handed to Apply mode (Ctrl-R)
+func NewEditor(app *tview.Application, title, text string, onSave, onApply func()) *Editor {
+	e := &Editor{
+		app:     app,
+		title:   title,
+		saved:   text,
+		onSave:  onSave,
+		onApply: onApply,
+	}
+
+	textArea := tview.NewTextArea().
+		SetWrap(false).
+		SetTextStyle(tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite))
+	textArea.SetText(text, false)
+	e.textArea = &numberedTextArea{TextArea: textArea}
+	return e
+}
+
+// Validate the text as it is edited, showing the diagnostics in a panel
+// next to it. Called off the UI goroutine, so validate may be slow; ctx ends
+// when newer text is validated, the editor is closed or after a timeout.
+func (e *Editor) SetValidator(validate func(ctx context.Context, text string) []validation.Diagnostic) {
+	e.validate = validate
+}
+
+func (e *Editor) CreateView() tview.Primitive {
+	e.textArea.SetChangedFunc(func() {
+		e.updateStatus()
+		e.scheduleValidation()
+	})
+	e.textArea.SetMovedFunc(e.updateStatus)
+	e.textArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			e.newline()
+		case tcell.KeyTab:
+			_, start, end := e.textArea.GetSelection()
+			e.textArea.Replace(start, end, editorIndent)
+		case tcell.KeyCtrlS:
+			e.onSave()
+		case tcell.KeyCtrlR:
+			e.onApply()
+		case tcell.KeyCtrlF:
+			e.searchInput.SetText(e.search)
+			e.layout.AddItem(e.searchInput, 1, 0, true)
+			e.app.SetFocus(e.searchInput)
+		case tcell.KeyCtrlN:
+			e.nextMatch()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	e.frame = tview.NewFlex().AddItem(e.textArea, 0, 1, true)
+	e.frame.SetBackgroundColor(tcell.ColorBlack)
+	e.frame.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitleColor(tcell.ColorWhite)
+
+	e.statusBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextColor(tcell.ColorLightGray)
+	e.statusBar.SetBackgroundColor(tcell.ColorBlack)
+
+	e.searchInput = tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabelColor(tcell.ColorLightBlue)
+	e.searchInput.SetDoneFunc(func(key tcell.Key) {
+		e.layout.RemoveItem(e.searchInput)
+		e.app.SetFocus(e.textArea)
+		if key == tcell.KeyEnter {
+			e.search = e.searchInput.GetText()
+			e.nextMatch()
+		}
+	})
+
+	main := tview.NewFlex().AddItem(e.frame, 0, 2, true)
+	if e.validate != nil {
+		e.validationView = tview.NewTextView().
+			SetDynamicColors(true).
+			SetWrap(true).
+			SetScrollable(true)
+		e.validationView.SetBackgroundColor(tcell.ColorBlack)
+		e.validationView.SetBorder(true).
+			SetBorderColor(tcell.ColorLightBlue).
+			SetTitle(" Validation ").
+			SetTitleColor(tcell.ColorWhite)
+		e.validationView.SetText("[gray]validating...[-]")
+		main.AddItem(e.validationView, 0, 1, false)
+	}
+
+	e.layout = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(main, 0, 1, true).
+		AddItem(e.statusBar, 1, 0, false)
+
+	e.updateStatus()
+	e.scheduleValidation()
+	return e.layout
+}
+
+func (e *Editor) Text() string {
+	return e.textArea.GetText()
+}
+
+// File the manifest was last saved to, empty if it never was
+func (e *Editor) Path() string {
+	return e.path
+}
+
+func (e *Editor) Title() string {
+	return e.title
+}
+
+// Whether the text changed since it was opened or last saved
+func (e *Editor) Modified() bool {
+	return e.textArea.GetText() != e.saved
+}
+
+// Record that the current text was written to path
+func (e *Editor) SetSaved(path string) {
+	e.path = path
+	e.saved = e.textArea.GetText()
+	e.SetMessage("saved to " + path)
+}
+
+// Show the outcome of an action in the status bar
+func (e *Editor) SetMessage(message string) {
+	e.message = message
+	e.updateStatus()
+}
+
+// Give the text area focus again, e.g. when returning from Apply mode
+func (e *Editor) Focus() {
+	e.app.SetFocus(e.textArea)
+}
+
+func (e *Editor) HasFocus() bool {
+	return e.layout.HasFocus()
+}
+
+// Insert a newline, keeping the indentation of the current line and
+// indenting further after a mapping key, list item or block scalar header
+func (e *Editor) newline() {
+	_, start, end := e.textArea.GetSelection()
+	text := e.textArea.GetText()
+	line := text[strings.LastIndexByte(text[:start], '\n')+1 : start]
+
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+	trimmed = strings.TrimSpace(trimmed)
+	if !strings.HasPrefix(trimmed, "#") {
+		if strings.HasPrefix(trimmed, "- ") {
+			indent += editorIndent // Further keys of the item line up with its first one
+		}
+		if strings.HasSuffix(trimmed, ":") || strings.HasSuffix(trimmed, "|") || strings.HasSuffix(trimmed, ">") ||
+			strings.HasSuffix(trimmed, "|-") || strings.HasSuffix(trimmed, ">-") {
+			indent += editorIndent
+		}
+	}
+
+	e.textArea.Replace(start, end, "\n"+indent)
+}
+
+// Select the next case-insensitive match of the search after the cursor,
+// wrapping around at the end
+func (e *Editor) nextMatch() {
+	if e.search == "" {
+		return
+	}
+	text := e.textArea.GetText()
+	_, _, cursor := e.textArea.GetSelection()
+
+	match := indexFold(text, e.search, cursor)
+	if match < 0 {
+		match = indexFold(text, e.search, 0)
+	}
+	if match < 0 {
+		e.SetMessage(fmt.Sprintf("[red]no match for %q[-]", tview.Escape(e.search)))
+		return
+	}
+	e.textArea.Select(match, match+len(e.search))
+
+	// Selecting keeps the scroll position, center the match if it is off screen
+	row, _, _, _ := e.textArea.GetCursor()
+	offset, _ := e.textArea.GetOffset()
+	_, _, _, height := e.textArea.GetInnerRect()
+	if row < offset || row >= offset+height {
+		e.textArea.SetOffset(max(0, row-height/2), 0)
+	}
+	e.SetMessage("")
+}
+
+// Validate once typing pauses
+func (e *Editor) scheduleValidation() {
+	if e.validate == nil {
+		return
+	}
+	e.validationGeneration++
+	generation, text := e.validationGeneration, e.textArea.GetText()
+
+	e.stopValidation()
+	ctx, cancel := context.WithTimeout(context.Background(), editorValidationDelay+editorValidationTimeout)
+	e.validationCancel = cancel
+	e.validationTimer = time.AfterFunc(editorValidationDelay, func() {
+		defer cancel()
+		diagnostics := e.validate(ctx, text)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return // Newer text is being validated or the editor was closed
+		}
+		e.app.QueueUpdateDraw(func() {
+			if generation == e.validationGeneration {
+				e.setDiagnostics(diagnostics)
+			}
+		})
+	})
+}
+
+// Stop a pending or running validation, e.g. when leaving the editor
+func (e *Editor) stopValidation() {
+	if e.validationTimer != nil {
+		e.validationTimer.Stop()
+	}
+	if e.validationCancel != nil {
+		e.validationCancel()
+	}
+}
+
+// Stop background work before the editor is discarded
+func (e *Editor) Close() {
+	e.stopValidation()
+}
+
+func (e *Editor) setDiagnostics(diagnostics []validation.Diagnostic) {
+	e.textArea.marks = make(map[int]validation.Severity)
+	e.validationView.Clear()
+	if len(diagnostics) == 0 {
+		e.validationView.SetText("[green]No problems found[-]")
+		return
+	}
+
+	for _, diagnostic := range diagnostics {
+		color := "red"
+		switch diagnostic.Severity {
+		case validation.SeverityWarning:
+			color = "yellow"
+		case validation.SeverityInfo:
+			color = "gray"
+		}
+		// Errors win over warnings, which win over info
+		if mark, ok := e.textArea.marks[diagnostic.Line-1]; !ok || mark == validation.SeverityInfo ||
+			(mark == validation.SeverityWarning && diagnostic.Severity == validation.SeverityError) {
+			e.textArea.marks[diagnostic.Line-1] = diagnostic.Severity
+		}
+		fmt.Fprintf(e.validationView, "[%s]%d:%d[-] %s\n", color, diagnostic.Line, diagnostic.Column, tview.Escape(diagnostic.Message))
+		if diagnostic.Path != "" {
+			fmt.Fprintf(e.validationView, "  [gray]%s[-]\n", tview.Escape(diagnostic.Path))
+		}
+	}
+	e.validationView.ScrollToBeginning()
+}
+
+func (e *Editor) updateStatus() {
+	title := e.title
+	if e.Modified() {
+		title += " [modified]"
+	}
+	e.frame.SetTitle(fmt.Sprintf(" Editor: %s ", tview.Escape(title)))
+
+	_, _, row, column := e.textArea.GetCursor()
+	status := fmt.Sprintf(" Ln %d, Col %d | save(Ctrl-S) apply(Ctrl-R) search(Ctrl-F) next(Ctrl-N) undo(Ctrl-Z) redo(Ctrl-Y) help(F1) close(Esc)", row+1, column+1)
+	if e.message != "" {
+		status = " " + e.message + " |" + status
+	}
+	e.statusBar.SetText(status)
+}
+
+// Byte offset of the first case-insensitive match of substr in s at or
+// after from, -1 if there is none
+func indexFold(s, substr string, from int) int {
+	for i := from; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Text area drawn with a gutter of line numbers on its left. Wrapping is off
+// so every row on screen is one line of text.
+type numberedTextArea struct {
+	*tview.TextArea
+	x, y, width, height int                         // Area including the gutter
+	marks               map[int]validation.Severity // Lines with diagnostics, 0-based
+}
+
+func (t *numberedTextArea) SetRect(x, y, width, height int) {
+	t.x, t.y, t.width, t.height = x, y, width, height
+	t.TextArea.SetRect(x, y, width, height)
+}
+
+func (t *numberedTextArea) Draw(screen tcell.Screen) {
+	lines := strings.Count(t.GetText(), "\n") + 1
+	gutter := len(strconv.Itoa(lines)) + 1
+	if gutter >= t.width {
+		gutter = 0
+	}
+
+	// The text area scrolls to the cursor while drawing, so number the
+	// lines afterwards
+	t.TextArea.SetRect(t.x+gutter, t.y, t.width-gutter, t.height)
+	t.TextArea.Draw(screen)
+
+	offset, _ := t.GetOffset()
+	_, _, cursorRow, _ := t.GetCursor()
+	for row := 0; row < t.height && gutter > 0; row++ {
+		line := offset + row
+		if line >= lines {
+			break
+		}
+		style := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorDarkGray)
+		if line == cursorRow {
+			style = style.Foreground(tcell.ColorWhite)
+		}
+		switch t.marks[line] {
+		case validation.SeverityError:
+			style = style.Foreground(tcell.ColorRed)
+		case validation.SeverityWarning:
+			style = style.Foreground(tcell.ColorYellow)
+		}
+		number := fmt.Sprintf("%*d ", gutter-1, line+1)
+		for i, r := range number {
+			screen.SetContent(t.x+i, t.y+row, r, nil, style)
+		}
+	}
+}
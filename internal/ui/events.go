@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Render events like kubectl events, with an OBJECT column when they are
+// about more than one object
+func RenderEvents(table *tview.Table, events []kubernetes.EventRecord, err error, showObject bool) {
+	table.Clear()
+
+	columns := []string{"LAST SEEN", "TYPE", "REASON"}
+	if showObject {
+		columns = append(columns, "OBJECT")
+	}
+	columns = append(columns, "COUNT", "FROM", "MESSAGE")
+	for col, name := range columns {
+		table.SetCell(0, col, headerCell(name))
+	}
+
+	message := ""
+	switch {
+	case err != nil:
+		message = fmt.Sprintf("Error loading events: %v", err)
+	case len(events) == 0:
+		message = "No events found"
+	}
+	if message != "" {
+		table.SetCell(1, 0, tview.NewTableCell(message).
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+
+	for i, event := range events {
+		color := tcell.ColorWhite
+		if event.Type == "Warning" {
+			color = tcell.ColorYellow
+		}
+
+		cells := []string{kubernetes.Age(event.LastSeen), event.Type, event.Reason}
+		if showObject {
+			cells = append(cells, event.Object)
+		}
+		cells = append(cells, fmt.Sprintf("%d", event.Count), event.Source, event.Message)
+
+		for col, text := range cells {
+			table.SetCell(i+1, col, tview.NewTableCell(text).SetTextColor(color))
+		}
+	}
+	table.Select(1, 0).ScrollToBeginning()
+}
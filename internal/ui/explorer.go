@@ -5,6 +5,8 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
 )
 
 type Explorer struct {
@@ -15,67 +17,168 @@ func NewExplorer(app *tview.Application) *Explorer {
 	return &Explorer{app: app}
 }
 
-func (e *Explorer) CreateExplorerView(namespace string, resourceType string) *tview.List {
-	list := tview.NewList()
-	list.SetMainTextColor(tcell.ColorWhite).
-		SetSelectedTextColor(tcell.ColorBlack).
-		SetSelectedBackgroundColor(tcell.ColorLightBlue).
-		SetBackgroundColor(tcell.ColorBlack)
+// Block of the explorer table, one per listed resource type
+type ExplorerSection struct {
+	Title   string                    // Heading shown when several sections are rendered
+	Table   *kubernetes.ResourceTable // Rows to render, nil when Message is set
+	Message string                    // Shown instead of rows, e.g. an error or "No pods found"
+}
+
+func (e *Explorer) CreateExplorerView(context string, namespace string, resourceType string) *tview.Table {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightBlue))
+	table.SetBackgroundColor(tcell.ColorBlack)
 
-	list.SetBorder(true).
+	table.SetBorder(true).
 		SetBorderColor(tcell.ColorLightBlue).
 		SetTitleColor(tcell.ColorWhite)
 
-	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Rune() {
-		case 'j':
-			return tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
-		case 'k':
-			return tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModNone)
-		}
-		return event
-	})
+	e.UpdateExplorerTitle(table, context, namespace, resourceType)
+	return table
+}
 
-	title := fmt.Sprintf(" Explorer Mode - Namespace: %s | Resource: %s (Press 'n'/'r' to change) ", namespace, resourceType)
-	list.SetTitle(title)
-	return list
+func (e *Explorer) UpdateExplorerTitle(table *tview.Table, context string, namespace string, resourceType string) {
+	title := fmt.Sprintf(" Explorer Mode - Context: %s | Namespace: %s | Resource: %s (Press 'c'/'n'/'r' to change) ", context, namespace, resourceType)
+	table.SetTitle(title)
 }
 
-func (e *Explorer) UpdateExplorerTitle(list *tview.List, namespace string, resourceType string) {
-	title := fmt.Sprintf(" Explorer Mode - Namespace: %s | Resource: %s (Press 'n'/'r' to change) ", namespace, resourceType)
-	list.SetTitle(title)
+// Render sections into the table. A single section keeps its column header
+// fixed; several are stacked with a heading each, like kubectl get all.
+func (e *Explorer) RenderTable(table *tview.Table, sections []ExplorerSection) {
+	table.Clear()
+	table.SetFixed(0, 0)
+
+	row := 0
+	for i, section := range sections {
+		if len(sections) > 1 {
+			if i > 0 {
+				row++ // Blank line between sections
+			}
+			table.SetCell(row, 0, headerCell(section.Title).SetTextColor(tcell.ColorYellow))
+			row++
+		}
+
+		if section.Message != "" {
+			table.SetCell(row, 0, tview.NewTableCell(section.Message).
+				SetTextColor(tcell.ColorWhite).
+				SetSelectable(false))
+			row++
+			continue
+		}
+
+		for col, column := range section.Table.Columns {
+			table.SetCell(row, col, headerCell(column.Name))
+		}
+		if len(sections) == 1 {
+			table.SetFixed(1, 0)
+		}
+		row++
+
+		for r := range section.Table.Rows {
+			resourceRow := &section.Table.Rows[r]
+			for col, text := range resourceRow.Cells {
+				table.SetCell(row, col, tview.NewTableCell(text).
+					SetTextColor(tcell.ColorWhite).
+					SetReference(resourceRow))
+			}
+			row++
+		}
+	}
 }
 
-func (e *Explorer) CreateResourceSelector(pages *tview.Pages, onSelect func(string)) {
-	resourceTypes := []string{"all", "pods", "services", "deployments", "configmaps", "secrets", "ingresses", "daemonsets", "statefulsets", "jobs", "cronjobs"}
-	title := " Resource Type Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
-	pageName := "resource-selector"
-	fs := NewFuzzySelector(resourceTypes, title, pageName, pages, onSelect)
+// Resource row under the cursor, nil when nothing selectable is selected
+func (e *Explorer) SelectedRow(table *tview.Table) *kubernetes.ResourceRow {
+	row, _ := table.GetSelection()
+	cell := table.GetCell(row, 0)
+	if resourceRow, ok := cell.GetReference().(*kubernetes.ResourceRow); ok {
+		return resourceRow
+	}
+	return nil
+}
 
-	inputField, matchList, err := fs.createSelector()
-	if err != nil {
-		return
+// Move the cursor to the first row matching, or the first resource row when
+// none does. Returns whether a matching row was found.
+func (e *Explorer) SelectRow(table *tview.Table, match func(*kubernetes.ResourceRow) bool) bool {
+	first := -1
+	for row := 0; row < table.GetRowCount(); row++ {
+		resourceRow, ok := table.GetCell(row, 0).GetReference().(*kubernetes.ResourceRow)
+		if !ok {
+			continue
+		}
+		if match != nil && match(resourceRow) {
+			table.Select(row, 0)
+			return true
+		}
+		if first < 0 {
+			first = row
+		}
 	}
-	// Create flex layout with input field on top and match list below
-	flex := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(inputField, 3, 0, true).
-		AddItem(matchList, 0, 1, false)
 
-	flex.SetBorder(true).
-		SetTitle(title).
-		SetBorderColor(tcell.ColorLightBlue).
-		SetTitleColor(tcell.ColorWhite).
-		SetBackgroundColor(tcell.ColorBlack)
+	if first >= 0 {
+		table.Select(first, 0)
+	}
+	return false
+}
 
-	pages.AddPage(pageName, flex, true, false)
-	pages.SwitchToPage(pageName)
+func headerCell(text string) *tview.TableCell {
+	return tview.NewTableCell(text).
+		SetTextColor(tcell.ColorLightBlue).
+		SetAttributes(tcell.AttrBold).
+		SetSelectable(false)
+}
 
+func (e *Explorer) CreateResourceSelector(resourceTypes []string, pages *tview.Pages, onSelect func(string)) {
+	title := " Resource Type Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
+	showFuzzySelector(NewFuzzySelector(resourceTypes, title, "resource-selector", pages, onSelect))
 }
+
 func (e *Explorer) CreateNamespaceSelector(namespaces []string, pages *tview.Pages, onSelect func(string)) {
 	title := " Namespace Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
-	pageName := "namespace-selector"
-	fs := NewFuzzySelector(namespaces, title, pageName, pages, onSelect)
+	showFuzzySelector(NewFuzzySelector(namespaces, title, "namespace-selector", pages, onSelect))
+}
+
+func (e *Explorer) CreateContextSelector(contexts []string, pages *tview.Pages, onSelect func(string)) {
+	title := " Context Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
+	showFuzzySelector(NewFuzzySelector(contexts, title, "context-selector", pages, onSelect))
+}
+
+// Pick one of a pod's containers, passing its name to onSelect
+func (e *Explorer) CreateContainerSelector(containers []kubernetes.PodContainer, pages *tview.Pages, onSelect func(string)) {
+	showContainerSelector(containers, pages, "container-selector", "", onSelect)
+}
+
+func (e *Explorer) CreateTemplateSelector(templates []string, pages *tview.Pages, onSelect func(string)) {
+	title := " Template Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
+	showFuzzySelector(NewFuzzySelector(templates, title, "template-selector", pages, onSelect))
+}
+
+// Fuzzy selector over containers labelled with their type, passing the name
+// of the picked one to onSelect. The selector returns to returnPage, or to
+// the explorer if it's empty.
+func showContainerSelector(containers []kubernetes.PodContainer, pages *tview.Pages, pageName, returnPage string, onSelect func(string)) {
+	labels := make([]string, 0, len(containers))
+	names := make(map[string]string)
+	for _, container := range containers {
+		label := container.Name
+		if container.Type != kubernetes.RegularContainer {
+			label = fmt.Sprintf("%s (%s)", container.Name, container.Type)
+		}
+		labels = append(labels, label)
+		names[label] = container.Name
+	}
+
+	title := " Container Selector (Ctrl+J/K to navigate, Enter to select, Esc to cancel) "
+	fs := NewFuzzySelector(labels, title, pageName, pages, func(label string) {
+		onSelect(names[label])
+	})
+	if returnPage != "" {
+		fs.SetReturnPage(returnPage)
+	}
+	showFuzzySelector(fs)
+}
+
+func showFuzzySelector(fs *FuzzySelector) {
 	inputField, matchList, err := fs.createSelector()
 	if err != nil {
 		return
@@ -87,12 +190,11 @@ func (e *Explorer) CreateNamespaceSelector(namespaces []string, pages *tview.Pag
 		AddItem(matchList, 0, 1, false)
 
 	flex.SetBorder(true).
-		SetTitle(title).
+		SetTitle(fs.title).
 		SetBorderColor(tcell.ColorLightBlue).
 		SetTitleColor(tcell.ColorWhite).
 		SetBackgroundColor(tcell.ColorBlack)
 
-	pages.AddPage(pageName, flex, true, false)
-	pages.SwitchToPage(pageName)
-
+	fs.pages.AddPage(fs.pageName, flex, true, false)
+	fs.pages.SwitchToPage(fs.pageName)
 }
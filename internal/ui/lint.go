@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/lint"
+)
+
+// Render lint findings in the order given, colored by severity
+func RenderLintFindings(table *tview.Table, findings []lint.Finding) {
+	table.Clear()
+
+	for col, name := range []string{"SEVERITY", "RULE", "FIELD", "MESSAGE"} {
+		table.SetCell(0, col, headerCell(name))
+	}
+
+	if len(findings) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No problems found").
+			SetTextColor(tcell.ColorGreen).
+			SetSelectable(false))
+		return
+	}
+
+	for i, finding := range findings {
+		color := tcell.ColorGray
+		switch finding.Severity {
+		case lint.SeverityError:
+			color = tcell.ColorRed
+		case lint.SeverityWarning:
+			color = tcell.ColorYellow
+		}
+
+		cells := []string{finding.Severity.String(), finding.Rule, finding.Path, finding.Message}
+		for col, text := range cells {
+			table.SetCell(i+1, col, tview.NewTableCell(text).SetTextColor(color))
+		}
+	}
+	table.Select(1, 0).ScrollToBeginning()
+}
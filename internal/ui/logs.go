@@ -0,0 +1,419 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Lines kept by the log view. Long-running follows drop the oldest lines
+// instead of growing without bound.
+const logBufferLines = 10000
+
+// How often streamed lines are flushed to the screen
+const logFlushInterval = 100 * time.Millisecond
+
+var (
+	logSinceOptions = []time.Duration{0, 5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+	logTailOptions  = []int64{500, 1000, 5000, -1} // -1 for all lines
+)
+
+// Fixed-capacity line buffer, overwriting the oldest line once full
+type LogBuffer struct {
+	lines []string
+	start int
+	count int
+}
+
+func NewLogBuffer(capacity int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, capacity)}
+}
+
+func (b *LogBuffer) Append(line string) {
+	if b.count < len(b.lines) {
+		b.lines[(b.start+b.count)%len(b.lines)] = line
+		b.count++
+		return
+	}
+	b.lines[b.start] = line
+	b.start = (b.start + 1) % len(b.lines)
+}
+
+// Lines from oldest to newest
+func (b *LogBuffer) Lines() []string {
+	lines := make([]string, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		lines = append(lines, b.lines[(b.start+i)%len(b.lines)])
+	}
+	return lines
+}
+
+func (b *LogBuffer) Len() int {
+	return b.count
+}
+
+func (b *LogBuffer) Cap() int {
+	return len(b.lines)
+}
+
+func (b *LogBuffer) Reset() {
+	b.start = 0
+	b.count = 0
+}
+
+// Opens a log stream for the given options, e.g. UnifiedClient.StreamLogs bound to a pod
+type LogStreamFunc func(ctx context.Context, opts kubernetes.LogOptions) (io.ReadCloser, error)
+
+type LogView struct {
+	app        *tview.Application
+	pages      *tview.Pages
+	podName    string
+	containers []kubernetes.PodContainer
+	openStream LogStreamFunc
+
+	options    kubernetes.LogOptions
+	sinceIndex int
+	tailIndex  int
+	buffer     *LogBuffer
+	state      string // Stream state shown in the status bar, e.g. "streaming" or "ended"
+
+	// Search within the buffer
+	search     string
+	matchCount int
+	matchIndex int
+
+	textView    *tview.TextView
+	statusBar   *tview.TextView
+	searchInput *tview.InputField
+	layout      *tview.Flex
+
+	cancel     context.CancelFunc
+	generation int // Incremented on every restart to drop output of old streams
+}
+
+func NewLogView(app *tview.Application, pages *tview.Pages, podName string, containers []kubernetes.PodContainer, container string, openStream LogStreamFunc) *LogView {
+	l := &LogView{
+		app:        app,
+		pages:      pages,
+		podName:    podName,
+		containers: containers,
+		openStream: openStream,
+		options: kubernetes.LogOptions{
+			Container: container,
+			Follow:    true,
+		},
+		buffer: NewLogBuffer(logBufferLines),
+	}
+	l.applyTail()
+	return l
+}
+
+func (l *LogView) CreateView() tview.Primitive {
+	l.textView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false).
+		SetScrollable(true).
+		SetMaxLines(l.buffer.Cap()).
+		SetTextColor(tcell.ColorWhite)
+	l.textView.SetBackgroundColor(tcell.ColorBlack)
+	l.textView.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitleColor(tcell.ColorWhite)
+
+	l.statusBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextColor(tcell.ColorLightGray)
+	l.statusBar.SetBackgroundColor(tcell.ColorBlack)
+
+	l.searchInput = tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabelColor(tcell.ColorLightBlue)
+	l.searchInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			l.setSearch(l.searchInput.GetText())
+		}
+		l.layout.RemoveItem(l.searchInput)
+		l.app.SetFocus(l.textView)
+	})
+
+	l.textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'f':
+			l.options.Follow = !l.options.Follow
+			l.restart()
+		case 'p':
+			l.options.Previous = !l.options.Previous
+			l.restart()
+		case 't':
+			l.options.Timestamps = !l.options.Timestamps
+			l.restart()
+		case 's':
+			l.sinceIndex = (l.sinceIndex + 1) % len(logSinceOptions)
+			l.restart()
+		case 'l':
+			l.tailIndex = (l.tailIndex + 1) % len(logTailOptions)
+			l.applyTail()
+			l.restart()
+		case 'c':
+			l.showContainerSelector()
+		case '/':
+			l.searchInput.SetText(l.search)
+			l.layout.AddItem(l.searchInput, 1, 0, true)
+			l.app.SetFocus(l.searchInput)
+		case 'n':
+			l.nextMatch(1)
+		case 'N':
+			l.nextMatch(-1)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	l.layout = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(l.textView, 0, 1, true).
+		AddItem(l.statusBar, 1, 0, false)
+
+	l.updateTitle()
+	return l.layout
+}
+
+// Start streaming with the current options
+func (l *LogView) Start() {
+	l.restart()
+}
+
+// Stop the running stream
+func (l *LogView) Stop() {
+	l.generation++
+	if l.cancel != nil {
+		l.cancel()
+		l.cancel = nil
+	}
+}
+
+// Restart the stream after an option change, discarding the buffer
+func (l *LogView) restart() {
+	l.Stop()
+
+	l.buffer.Reset()
+	l.textView.Clear()
+	l.matchCount = 0
+	l.matchIndex = 0
+	l.state = "connecting"
+	l.updateTitle()
+
+	opts := l.options
+	if since := logSinceOptions[l.sinceIndex]; since > 0 {
+		sinceTime := time.Now().Add(-since)
+		opts.SinceTime = &sinceTime
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	go l.stream(ctx, l.generation, opts)
+}
+
+// Read the stream line by line, flushing batches to the UI goroutine
+func (l *LogView) stream(ctx context.Context, generation int, opts kubernetes.LogOptions) {
+	reader, err := l.openStream(ctx, opts)
+	if err != nil {
+		l.app.QueueUpdateDraw(func() {
+			if generation == l.generation {
+				l.state = fmt.Sprintf("[red]error: %s[-]", tview.Escape(err.Error()))
+				l.updateTitle()
+			}
+		})
+		return
+	}
+	defer reader.Close()
+
+	lines := make(chan string, 1000)
+	go func() {
+		defer close(lines)
+		bufReader := bufio.NewReader(reader)
+		for {
+			line, err := bufReader.ReadString('\n')
+			if line != "" {
+				select {
+				case lines <- strings.TrimRight(line, "\r\n"):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	l.app.QueueUpdateDraw(func() {
+		if generation == l.generation {
+			l.state = "streaming"
+			l.updateTitle()
+		}
+	})
+
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	var pending []string
+	flush := func(state string) {
+		batch := pending
+		pending = nil
+		l.app.QueueUpdateDraw(func() {
+			if generation != l.generation {
+				return
+			}
+			l.appendLines(batch)
+			if state != "" {
+				l.state = state
+			}
+			l.updateTitle()
+		})
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				flush("ended")
+				return
+			}
+			pending = append(pending, line)
+		case <-ticker.C:
+			if len(pending) > 0 {
+				flush("")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *LogView) appendLines(lines []string) {
+	var text strings.Builder
+	for _, line := range lines {
+		l.buffer.Append(line)
+		text.WriteString(l.highlight(line))
+		text.WriteByte('\n')
+	}
+	fmt.Fprint(l.textView, text.String())
+
+	if l.options.Follow && l.search == "" {
+		l.textView.ScrollToEnd()
+	}
+}
+
+// Escape a line for display, wrapping search matches in numbered regions
+func (l *LogView) highlight(line string) string {
+	if l.search == "" {
+		return tview.Escape(line)
+	}
+
+	// Match case-insensitively unless lowering changes byte offsets
+	haystack, needle := strings.ToLower(line), strings.ToLower(l.search)
+	if len(haystack) != len(line) || len(needle) != len(l.search) {
+		haystack, needle = line, l.search
+	}
+
+	var text strings.Builder
+	pos := 0
+	for {
+		i := strings.Index(haystack[pos:], needle)
+		if i < 0 {
+			break
+		}
+		start := pos + i
+		end := start + len(needle)
+		text.WriteString(tview.Escape(line[pos:start]))
+		fmt.Fprintf(&text, `["m%d"][black:yellow]%s[-:-][""]`, l.matchCount, tview.Escape(line[start:end]))
+		l.matchCount++
+		pos = end
+	}
+	text.WriteString(tview.Escape(line[pos:]))
+	return text.String()
+}
+
+func (l *LogView) setSearch(search string) {
+	l.search = search
+	l.matchCount = 0
+	l.matchIndex = 0
+
+	var text strings.Builder
+	for _, line := range l.buffer.Lines() {
+		text.WriteString(l.highlight(line))
+		text.WriteByte('\n')
+	}
+	l.textView.SetText(text.String())
+	l.textView.Highlight()
+
+	if l.matchCount > 0 {
+		l.matchIndex = -1
+		l.nextMatch(1)
+	}
+	l.updateTitle()
+}
+
+func (l *LogView) nextMatch(step int) {
+	if l.matchCount == 0 {
+		return
+	}
+	l.matchIndex = (l.matchIndex + step + l.matchCount) % l.matchCount
+	l.textView.Highlight(fmt.Sprintf("m%d", l.matchIndex)).ScrollToHighlight()
+	l.updateTitle()
+}
+
+func (l *LogView) showContainerSelector() {
+	showContainerSelector(l.containers, l.pages, "log-container-selector", "logs", func(container string) {
+		l.options.Container = container
+		l.restart()
+	})
+}
+
+func (l *LogView) applyTail() {
+	l.options.TailLines = nil
+	if tail := logTailOptions[l.tailIndex]; tail >= 0 {
+		l.options.TailLines = &tail
+	}
+}
+
+func (l *LogView) updateTitle() {
+	l.textView.SetTitle(fmt.Sprintf(" Logs: %s/%s (Press Esc to return) ", l.podName, l.options.Container))
+
+	since := "all"
+	if d := logSinceOptions[l.sinceIndex]; d > 0 {
+		since = d.String()
+	}
+	tail := "all"
+	if l.options.TailLines != nil {
+		tail = fmt.Sprintf("%d", *l.options.TailLines)
+	}
+
+	status := fmt.Sprintf(" %s | follow(f):%s previous(p):%s timestamps(t):%s since(s):%s tail(l):%s container(c) | %d/%d lines",
+		l.state, onOff(l.options.Follow), onOff(l.options.Previous), onOff(l.options.Timestamps),
+		since, tail, l.buffer.Len(), l.buffer.Cap())
+	if l.search != "" {
+		status += fmt.Sprintf(" | /%s %d/%d", tview.Escape(l.search), min(l.matchIndex+1, l.matchCount), l.matchCount)
+	}
+	l.statusBar.SetText(status)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// Lists active port forwards, stopping the selected one with 'd'
+type PortForwardPanel struct {
+	table  *tview.Table
+	onStop func(*kubernetes.PortForward)
+}
+
+func NewPortForwardPanel(onStop func(*kubernetes.PortForward)) *PortForwardPanel {
+	return &PortForwardPanel{onStop: onStop}
+}
+
+func (p *PortForwardPanel) CreateView() tview.Primitive {
+	p.table = tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	p.table.SetBackgroundColor(tcell.ColorBlack)
+	p.table.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitle(" Port Forwards (Press 'd' to stop, Esc to return) ").
+		SetTitleColor(tcell.ColorWhite)
+
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() != 'd' {
+			return event
+		}
+		row, _ := p.table.GetSelection()
+		if forward, ok := p.table.GetCell(row, 0).GetReference().(*kubernetes.PortForward); ok {
+			p.onStop(forward)
+		}
+		return nil
+	})
+
+	return p.table
+}
+
+func (p *PortForwardPanel) Render(forwards []*kubernetes.PortForward) {
+	selected, _ := p.table.GetSelection()
+	p.table.Clear()
+
+	for col, name := range []string{"LOCAL", "SOURCE", "POD", "PORT", "STATUS"} {
+		p.table.SetCell(0, col, headerCell(name))
+	}
+
+	if len(forwards) == 0 {
+		p.table.SetCell(1, 0, tview.NewTableCell("No active port forwards, press 'f' on a pod or service in the explorer").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+
+	for i, forward := range forwards {
+		status := "[green]running[-]"
+		select {
+		case <-forward.Done():
+			status = "[red]stopped[-]"
+			if err := forward.Err(); err != nil {
+				status = fmt.Sprintf("[red]failed: %s[-]", tview.Escape(err.Error()))
+			}
+		default:
+		}
+
+		cells := []string{
+			fmt.Sprintf("localhost:%d", forward.LocalPort),
+			forward.Target.Source,
+			fmt.Sprintf("%s/%s", forward.Target.Namespace, forward.Target.Pod),
+			forward.Target.String(),
+			status,
+		}
+		for col, text := range cells {
+			p.table.SetCell(i+1, col, tview.NewTableCell(text).
+				SetTextColor(tcell.ColorWhite).
+				SetReference(forward))
+		}
+	}
+
+	p.table.Select(max(1, min(selected, len(forwards))), 0)
+}
+
+// Form to pick the port of a pod or service to forward and the local port.
+// A single target without a container port asks for the pod port instead,
+// for pods that do not declare their ports.
+func NewPortForwardForm(targets []kubernetes.PortForwardTarget, onStart func(target kubernetes.PortForwardTarget, localPort int32), onCancel func()) tview.Primitive {
+	form := tview.NewForm()
+	selected := targets[0]
+	askPodPort := len(targets) == 1 && selected.ContainerPort == 0
+
+	localPort := tview.NewInputField().
+		SetLabel("Local port").
+		SetFieldWidth(8).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	if !askPodPort {
+		localPort.SetText(strconv.Itoa(int(selected.Port)))
+	}
+
+	if askPodPort {
+		form.AddInputField("Pod port", "", 8, tview.InputFieldInteger, func(text string) {
+			port, _ := strconv.Atoi(text)
+			selected.Port = int32(port)
+			selected.ContainerPort = int32(port)
+			localPort.SetText(text)
+		})
+	} else {
+		options := make([]string, 0, len(targets))
+		for _, target := range targets {
+			options = append(options, target.String())
+		}
+		form.AddDropDown("Remote port", options, 0, func(option string, index int) {
+			if index < 0 {
+				return
+			}
+			selected = targets[index]
+			localPort.SetText(strconv.Itoa(int(selected.Port)))
+		})
+	}
+	form.AddFormItem(localPort)
+
+	form.AddButton("Start", func() {
+		port, err := strconv.Atoi(localPort.GetText())
+		if err != nil || port < 0 || port > 65535 || selected.ContainerPort <= 0 {
+			return
+		}
+		onStart(selected, int32(port))
+	})
+	form.AddButton("Cancel", onCancel)
+	form.SetCancelFunc(onCancel)
+
+	form.SetBackgroundColor(tcell.ColorBlack)
+	form.SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	form.SetButtonBackgroundColor(tcell.ColorLightBlue)
+	form.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitle(fmt.Sprintf(" Port Forward: %s (0 picks a free local port) ", selected.Source)).
+		SetTitleColor(tcell.ColorWhite)
+
+	// Center the form over the explorer
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 9, 1, true).
+			AddItem(nil, 0, 1, false), 60, 1, true).
+		AddItem(nil, 0, 1, false)
+}
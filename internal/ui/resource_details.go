@@ -5,12 +5,28 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"kubeguide/internal/kubernetes"
+	"kubeguide/internal/lint"
 )
 
 type ResourceDetails struct {
 	name         string
 	resourceType string
 	content      string
+
+	// Describe-style summary, shown instead of the YAML until toggled with 'd'
+	description  string
+	showDescribe bool
+
+	// Best-practice findings, shown under the YAML once set
+	showLint     bool
+	lintFindings []lint.Finding
+
+	// Events about the object, shown under the YAML once set
+	showEvents bool
+	events     []kubernetes.EventRecord
+	eventsErr  error
 }
 
 func NewResourceDetails(name string, resourceType string, content string) ResourceDetails {
@@ -21,14 +37,74 @@ func NewResourceDetails(name string, resourceType string, content string) Resour
 	}
 }
 
+func (r *ResourceDetails) SetEvents(events []kubernetes.EventRecord, err error) {
+	r.showEvents = true
+	r.events = events
+	r.eventsErr = err
+}
+
+func (r *ResourceDetails) SetLintFindings(findings []lint.Finding) {
+	r.showLint = true
+	r.lintFindings = findings
+}
+
+func (r *ResourceDetails) SetDescription(description string) {
+	r.description = description
+	r.showDescribe = description != ""
+}
+
 func (r *ResourceDetails) CreateView() tview.Primitive {
 	textView := tview.NewTextView().
 		SetTextColor(tcell.ColorWhite)
-	fmt.Fprintf(textView, "%s", r.content)
 	textView.SetBorder(true).
 		SetBorderColor(tcell.ColorLightBlue).
-		SetTitle(fmt.Sprintf(" %s: %s (Press Esc to return) ", r.resourceType, r.name)).
 		SetTitleColor(tcell.ColorWhite)
+	r.renderContent(textView)
 
-	return textView
+	if r.description != "" {
+		textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Rune() == 'd' {
+				r.showDescribe = !r.showDescribe
+				r.renderContent(textView)
+				return nil
+			}
+			return event
+		})
+	}
+
+	if !r.showLint && !r.showEvents {
+		return textView
+	}
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(textView, 0, 2, true)
+	if r.showLint {
+		lintTable := NewListTable(fmt.Sprintf(" Best Practices (%d) ", len(r.lintFindings)))
+		RenderLintFindings(lintTable, r.lintFindings)
+		layout.AddItem(lintTable, 0, 1, false)
+	}
+	if r.showEvents {
+		eventsTable := NewListTable(fmt.Sprintf(" Events (%d) ", len(r.events)))
+		RenderEvents(eventsTable, r.events, r.eventsErr, false)
+		layout.AddItem(eventsTable, 0, 1, false)
+	}
+	return layout
+}
+
+// Fill the text view with the summary or the YAML, whichever is selected
+func (r *ResourceDetails) renderContent(textView *tview.TextView) {
+	textView.Clear()
+	if r.showDescribe {
+		fmt.Fprintf(textView, "%s", r.description)
+		textView.SetTitle(fmt.Sprintf(" %s: %s - Describe (Press 'd' for YAML, Esc to return) ", r.resourceType, r.name))
+	} else {
+		fmt.Fprintf(textView, "%s", r.content)
+		title := fmt.Sprintf(" %s: %s (Press Esc to return) ", r.resourceType, r.name)
+		if r.description != "" {
+			title = fmt.Sprintf(" %s: %s - YAML (Press 'd' for describe, Esc to return) ", r.resourceType, r.name)
+		}
+		textView.SetTitle(title)
+	}
+	textView.ScrollToBeginning()
 }
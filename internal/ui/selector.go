@@ -7,24 +7,32 @@ import (
 )
 
 type FuzzySelector struct {
-	title    string
-	pageName string
-	pages    *tview.Pages
-	onSelect func(string)
-	items    []string
+	title      string
+	pageName   string
+	returnPage string // Page shown again once the selector closes
+	pages      *tview.Pages
+	onSelect   func(string)
+	items      []string
 }
 
 func NewFuzzySelector(items []string, title string, pageName string, pages *tview.Pages, onSelect func(string)) *FuzzySelector {
 	fs := FuzzySelector{
-		title:    title,
-		pageName: pageName,
-		pages:    pages,
-		onSelect: onSelect,
-		items:    items,
+		title:      title,
+		pageName:   pageName,
+		returnPage: "explorer",
+		pages:      pages,
+		onSelect:   onSelect,
+		items:      items,
 	}
 	return &fs
 }
 
+// Return to another page than the explorer once the selector closes
+func (fs *FuzzySelector) SetReturnPage(pageName string) *FuzzySelector {
+	fs.returnPage = pageName
+	return fs
+}
+
 func (fs *FuzzySelector) createSelector() (*tview.InputField, *tview.List, error) {
 	var filteredMatches []fuzzy.Match
 	var selectedIndex int
@@ -95,12 +103,12 @@ func (fs *FuzzySelector) createSelector() (*tview.InputField, *tview.List, error
 				selectedItem := filteredMatches[selectedIndex].Str
 				fs.onSelect(selectedItem)
 				fs.pages.RemovePage(fs.pageName)
-				fs.pages.SwitchToPage("explorer")
+				fs.pages.SwitchToPage(fs.returnPage)
 			}
 			return nil
 		case tcell.KeyEscape: // Cancel
 			fs.pages.RemovePage(fs.pageName)
-			fs.pages.SwitchToPage("explorer")
+			fs.pages.SwitchToPage(fs.returnPage)
 			return nil
 		}
 		return event
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Bordered table of selectable rows under a fixed header row, e.g. events or
+// deprecated APIs
+func NewListTable(title string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetBorder(true).
+		SetBorderColor(tcell.ColorLightBlue).
+		SetTitle(title).
+		SetTitleColor(tcell.ColorWhite)
+	return table
+}
@@ -0,0 +1,264 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apiextclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/openapi"
+)
+
+// How long a lookup from the cluster may take, whoever is waiting for it
+const clusterLookupTimeout = 30 * time.Second
+
+// How long a failed lookup is reported again before the cluster is asked
+// anew, so an unreachable cluster isn't asked on every change in the editor
+const failedLookupTTL = 10 * time.Second
+
+// Schemas of the kinds a cluster serves, from its OpenAPI v3 endpoint with the
+// CRDs' openAPIV3Schema for custom resources the endpoint doesn't describe
+type ClusterSchemas struct {
+	discovery discovery.DiscoveryInterface
+	crdClient apiextclient.Interface
+	cacheDir  string // Empty disables the disk cache
+
+	mu      sync.Mutex // Guards lookups, never held while talking to the cluster
+	lookups map[string]*lookup
+}
+
+// Result of a lookup from the cluster, shared by everyone asking for it
+// while it runs and afterwards
+type lookup struct {
+	done     chan struct{} // Closed once value and err are set
+	value    any
+	err      error
+	finished time.Time
+}
+
+// Downloaded documents are cached in cacheDir, see DefaultCacheDir
+func NewClusterSchemas(discoveryClient discovery.DiscoveryInterface, crdClient apiextclient.Interface, cacheDir string) *ClusterSchemas {
+	return &ClusterSchemas{
+		discovery: discoveryClient,
+		crdClient: crdClient,
+		cacheDir:  cacheDir,
+		lookups:   make(map[string]*lookup),
+	}
+}
+
+// Cache directory for OpenAPI documents, empty if the user has no cache directory
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubeguide", "openapi")
+}
+
+func (c *ClusterSchemas) Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error) {
+	doc, err := c.document(ctx, groupVersionPath(gvk.GroupVersion()))
+	if err != nil {
+		return nil, err
+	}
+	if doc != nil {
+		if s := doc.Kind(gvk); s != nil {
+			return s, nil
+		}
+	}
+
+	// CRDs without a structural schema are missing from the OpenAPI documents
+	crdSchemas, err := sharedLookup(ctx, c, "crds", c.loadCRDSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return crdSchemas[gvk], nil
+}
+
+// Result of load, run once for everyone asking for key. Waiting stops when
+// ctx ends, the lookup goes on for those that come later; failed lookups are
+// run again after failedLookupTTL.
+func sharedLookup[T any](ctx context.Context, c *ClusterSchemas, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+	l, ok := c.lookups[key]
+	if ok && l.err != nil && time.Since(l.finished) > failedLookupTTL {
+		ok = false
+	}
+	if !ok {
+		l = &lookup{done: make(chan struct{})}
+		c.lookups[key] = l
+		go func() {
+			// Not the caller's context, others may wait for the result
+			ctx, cancel := context.WithTimeout(context.Background(), clusterLookupTimeout)
+			defer cancel()
+			value, err := load(ctx)
+
+			c.mu.Lock()
+			l.value, l.err, l.finished = value, err, time.Now()
+			c.mu.Unlock()
+			close(l.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	var zero T
+	select {
+	case <-l.done:
+		if l.err != nil {
+			return zero, l.err
+		}
+		return l.value.(T), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// OpenAPI v3 path of a group version, e.g. "api/v1" or "apis/apps/v1"
+func groupVersionPath(gv schema.GroupVersion) string {
+	if gv.Group == "" {
+		return "api/" + gv.Version
+	}
+	return "apis/" + gv.Group + "/" + gv.Version
+}
+
+// Document of a group version path, nil if the cluster doesn't serve it
+func (c *ClusterSchemas) document(ctx context.Context, path string) (*Document, error) {
+	paths, err := sharedLookup(ctx, c, "paths", func(context.Context) (map[string]openapi.GroupVersion, error) {
+		paths, err := c.discovery.OpenAPIV3().Paths()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list OpenAPI v3 schemas: %w", err)
+		}
+		return paths, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	groupVersion, ok := paths[path]
+	if !ok {
+		return nil, nil
+	}
+
+	return sharedLookup(ctx, c, "document "+path, func(context.Context) (*Document, error) {
+		data, err := c.fetch(path, groupVersion)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := ParseDocument(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return doc, nil
+	})
+}
+
+// Read a document from the disk cache or download and cache it. The server
+// publishes a hash of every document, so a changed schema gets a new file.
+func (c *ClusterSchemas) fetch(path string, groupVersion openapi.GroupVersion) ([]byte, error) {
+	cacheFile := ""
+	if c.cacheDir != "" {
+		if hash := documentHash(groupVersion.ServerRelativeURL()); hash != "" {
+			cacheFile = filepath.Join(c.cacheDir, strings.ReplaceAll(path, "/", "_")+"-"+hash+".json")
+			if data, err := os.ReadFile(cacheFile); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	data, err := groupVersion.Schema("application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OpenAPI schema of %s: %w", path, err)
+	}
+
+	// The cache only saves downloads, failing to write it is not an error
+	if cacheFile != "" {
+		if err := os.MkdirAll(c.cacheDir, 0o755); err == nil {
+			tmp := cacheFile + ".tmp"
+			if err := os.WriteFile(tmp, data, 0o644); err == nil {
+				os.Rename(tmp, cacheFile)
+			}
+		}
+	}
+	return data, nil
+}
+
+// Value of the hash query parameter in a document URL, e.g.
+// "/openapi/v3/apis/apps/v1?hash=014FBFF..."
+func documentHash(serverRelativeURL string) string {
+	parsed, err := url.Parse(serverRelativeURL)
+	if err != nil {
+		return ""
+	}
+	hash := parsed.Query().Get("hash")
+	// Used in a file name
+	if strings.ContainsAny(hash, `/\.`) {
+		return ""
+	}
+	return hash
+}
+
+func (c *ClusterSchemas) loadCRDSchemas(ctx context.Context) (map[schema.GroupVersionKind]*Schema, error) {
+	crdList, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	crdSchemas := make(map[schema.GroupVersionKind]*Schema)
+	for i := range crdList.Items {
+		schemas, err := CRDSchemas(&crdList.Items[i])
+		if err != nil {
+			continue // One broken CRD shouldn't hide the others
+		}
+		for gvk, s := range schemas {
+			crdSchemas[gvk] = s
+		}
+	}
+	return crdSchemas, nil
+}
+
+// Write the documents of the cluster's built-in group versions to dir, named
+// as FileSchemas reads them, and return how many were written. Groups defined
+// by CRDs are left out, offline validation takes those from CRD files.
+func (c *ClusterSchemas) Export(ctx context.Context, dir string) (int, error) {
+	paths, err := c.discovery.OpenAPIV3().Paths()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list OpenAPI v3 schemas: %w", err)
+	}
+	crdList, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+	crdGroups := make(map[string]bool)
+	for _, crd := range crdList.Items {
+		crdGroups[crd.Spec.Group] = true
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for path, groupVersion := range paths {
+		// Only group versions hold kinds, other paths are e.g. "version" or "apis"
+		if !strings.HasPrefix(path, "api/") && !strings.HasPrefix(path, "apis/") {
+			continue
+		}
+		if group, _, _ := strings.Cut(strings.TrimPrefix(path, "apis/"), "/"); strings.HasPrefix(path, "apis/") && crdGroups[group] {
+			continue
+		}
+		data, err := c.fetch(path, groupVersion)
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, DocumentFileName(path)), data, 0o644); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
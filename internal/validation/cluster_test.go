@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSharedLookupWaitsOnlyAsLongAsTheCaller(t *testing.T) {
+	c := NewClusterSchemas(nil, nil, "")
+	release := make(chan struct{})
+	var calls atomic.Int32
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release // A slow cluster
+		return "schema", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := sharedLookup(ctx, c, "key", load); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want the caller's deadline", err)
+	}
+
+	// The lookup goes on and later callers share it
+	close(release)
+	value, err := sharedLookup(context.Background(), c, "key", load)
+	if err != nil || value != "schema" {
+		t.Fatalf("lookup = %q, %v", value, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loaded %d times, want once", n)
+	}
+}
+
+func TestSharedLookupRetriesFailuresLater(t *testing.T) {
+	c := NewClusterSchemas(nil, nil, "")
+	var calls atomic.Int32
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		return "", errors.New("connection refused")
+	}
+
+	for range 3 {
+		if _, err := sharedLookup(context.Background(), c, "key", load); err == nil {
+			t.Fatal("no error")
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loaded %d times within the TTL, want once", n)
+	}
+
+	c.lookups["key"].finished = time.Now().Add(-failedLookupTTL - time.Second)
+	sharedLookup(context.Background(), c, "key", load)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("loaded %d times after the TTL, want twice", n)
+	}
+}
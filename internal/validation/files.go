@@ -0,0 +1,271 @@
+package validation
+
+import (
+	"compress/gzip"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"kubeguide/internal/kubernetes"
+)
+
+// OpenAPI v3 documents embedded in the binary, one directory per Kubernetes
+// version, e.g. "schemas/v1.33". See schemas/README.md for what they cover.
+//
+//go:embed all:schemas
+var bundle embed.FS
+
+// Where offline validation gets its schemas from, typically command line flags
+type OfflineOptions struct {
+	SchemaDir   string   // Directory of OpenAPI v3 documents, optionally with a subdirectory per version
+	KubeVersion string   // Kubernetes version of the schemas, e.g. "v1.33"
+	CRDFiles    []string // YAML files with CustomResourceDefinitions
+}
+
+// Sources for the options: the CRD files first, then the schema directory or,
+// with only a version given, the bundled schemas of that version. Empty when
+// no option is set.
+func OfflineSources(opts OfflineOptions) ([]SchemaSource, error) {
+	var sources []SchemaSource
+	if len(opts.CRDFiles) > 0 {
+		crds, err := LoadCRDFiles(opts.CRDFiles...)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, crds)
+	}
+
+	switch {
+	case opts.SchemaDir != "":
+		schemas, err := SchemaDir(opts.SchemaDir, opts.KubeVersion)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, schemas)
+	case opts.KubeVersion != "":
+		schemas, err := BundledSchemas(opts.KubeVersion)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, schemas)
+	}
+	return sources, nil
+}
+
+// Schemas of built-in kinds from OpenAPI v3 documents stored as files, one
+// per group version, named like the cluster's paths: "api__v1_openapi.json",
+// "apis__apps__v1_openapi.json". Documents may be gzipped with a ".gz" suffix.
+type FileSchemas struct {
+	fsys fs.FS
+
+	mu        sync.Mutex
+	documents map[string]*Document // Parsed documents by path, nil for missing files
+}
+
+func NewFileSchemas(fsys fs.FS) *FileSchemas {
+	return &FileSchemas{fsys: fsys, documents: make(map[string]*Document)}
+}
+
+// Schemas in dir, or in its subdirectory for version when one is given. A
+// missing version directory is an error, as nothing would be validated.
+func SchemaDir(dir, version string) (*FileSchemas, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("schema directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("schema directory: %s is not a directory", dir)
+	}
+
+	if version != "" {
+		versionDir := filepath.Join(dir, normalizeVersion(version))
+		if info, err := os.Stat(versionDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("no schemas for Kubernetes %s in %s, export them with kubeguide export-schemas", normalizeVersion(version), dir)
+		}
+		dir = versionDir
+	}
+	return NewFileSchemas(os.DirFS(dir)), nil
+}
+
+// Schemas bundled in the binary for a Kubernetes version
+func BundledSchemas(version string) (*FileSchemas, error) {
+	version = normalizeVersion(version)
+	if !slices.Contains(BundledVersions(), version) {
+		return nil, fmt.Errorf("no bundled schemas for Kubernetes %s, available: %s, or pass --schema-dir", version, strings.Join(BundledVersions(), ", "))
+	}
+	fsys, err := fs.Sub(bundle, "schemas/"+version)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileSchemas(fsys), nil
+}
+
+// Schemas bundled for the newest Kubernetes version, nil if none are bundled
+func NewestBundledSchemas() *FileSchemas {
+	versions := BundledVersions()
+	if len(versions) == 0 {
+		return nil
+	}
+	schemas, err := BundledSchemas(versions[len(versions)-1])
+	if err != nil {
+		return nil
+	}
+	return schemas
+}
+
+// Kubernetes versions with bundled schemas, oldest first
+func BundledVersions() []string {
+	entries, _ := bundle.ReadDir("schemas")
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	slices.SortFunc(versions, kubernetes.CompareKubeVersions)
+	return versions
+}
+
+func (f *FileSchemas) Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := groupVersionPath(gvk.GroupVersion())
+	doc, ok := f.documents[path]
+	if !ok {
+		var err error
+		doc, err = f.load(path)
+		if err != nil {
+			return nil, err
+		}
+		f.documents[path] = doc
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return doc.Kind(gvk), nil
+}
+
+// Document of a group version path, nil if there is no file for it
+func (f *FileSchemas) load(path string) (*Document, error) {
+	name := DocumentFileName(path)
+	data, err := fs.ReadFile(f.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		name += ".gz"
+		data, err = readGzipFile(f.fsys, name)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	doc, err := ParseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// File name of the document of a group version path, e.g.
+// "apis__apps__v1_openapi.json" for "apis/apps/v1"
+func DocumentFileName(path string) string {
+	return strings.ReplaceAll(path, "/", "__") + "_openapi.json"
+}
+
+func readGzipFile(fsys fs.FS, name string) ([]byte, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// Schemas of the custom resources defined in CRD manifests on disk
+type CRDFileSchemas struct {
+	schemas map[schema.GroupVersionKind]*Schema
+}
+
+// Load the CustomResourceDefinitions in YAML or JSON files. Other kinds in
+// the files are skipped, so a chart's rendered output can be passed as is.
+func LoadCRDFiles(paths ...string) (*CRDFileSchemas, error) {
+	c := &CRDFileSchemas{schemas: make(map[schema.GroupVersionKind]*Schema)}
+	for _, path := range paths {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *CRDFileSchemas) Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error) {
+	return c.schemas[gvk], nil
+}
+
+func (c *CRDFileSchemas) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read CRD file: %w", err)
+	}
+	defer file.Close()
+
+	found := 0
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var crd apiextv1.CustomResourceDefinition
+		err := decoder.Decode(&crd)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if crd.Kind != "CustomResourceDefinition" {
+			continue
+		}
+
+		schemas, err := CRDSchemas(&crd)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for gvk, s := range schemas {
+			c.schemas[gvk] = s
+		}
+		found++
+	}
+
+	if found == 0 {
+		return fmt.Errorf("%s: no CustomResourceDefinition found", path)
+	}
+	return nil
+}
+
+// Version as export-schemas and the bundle name directories, "1.33.2"
+// becomes "v1.33"
+func normalizeVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return "v" + strings.Join(parts, ".")
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchemaDirVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "v1.33"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		version string
+		wantErr string
+	}{
+		{version: ""},
+		{version: "v1.33"},
+		{version: "1.33.2"},
+		{version: "v1.34", wantErr: "no schemas for Kubernetes v1.34"},
+		{version: "v1.3", wantErr: "no schemas for Kubernetes v1.3"},
+	}
+	for _, tt := range tests {
+		_, err := SchemaDir(dir, tt.version)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("SchemaDir(%q): %v", tt.version, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("SchemaDir(%q) error = %v, want %q", tt.version, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBundledSchemas(t *testing.T) {
+	if _, err := BundledSchemas("v1.0"); err == nil {
+		t.Error("no error for a version that isn't bundled")
+	}
+
+	schemas, err := BundledSchemas("1.33.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: two
+  selector:
+    matchLabels: {app: web}
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        imagePort: 80
+`
+	var got []string
+	for _, diagnostic := range NewValidator(schemas).Validate(t.Context(), []byte(manifest)) {
+		got = append(got, diagnostic.String())
+	}
+	want := []string{
+		`6:13 spec.replicas: expected integer, got string`,
+		`14:9 spec.template.spec.containers[0].imagePort: unknown field "imagePort"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diagnostics:\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OpenAPI v3 schema, limited to what validation looks at. Kubernetes
+// publishes these per group version, CRDs carry them in openAPIV3Schema.
+type Schema struct {
+	Type                  string                `json:"type,omitempty"`
+	Format                string                `json:"format,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Properties            map[string]*Schema    `json:"properties,omitempty"`
+	AdditionalProperties  *AdditionalProperties `json:"additionalProperties,omitempty"`
+	Items                 *Schema               `json:"items,omitempty"`
+	Required              []string              `json:"required,omitempty"`
+	Enum                  []any                 `json:"enum,omitempty"`
+	Nullable              bool                  `json:"nullable,omitempty"`
+	AllOf                 []*Schema             `json:"allOf,omitempty"`
+	OneOf                 []*Schema             `json:"oneOf,omitempty"`
+	AnyOf                 []*Schema             `json:"anyOf,omitempty"`
+	Ref                   string                `json:"$ref,omitempty"`
+	PreserveUnknownFields bool                  `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	IntOrString           bool                  `json:"x-kubernetes-int-or-string,omitempty"`
+	EmbeddedResource      bool                  `json:"x-kubernetes-embedded-resource,omitempty"`
+	GroupVersionKinds     []schemaGVK           `json:"x-kubernetes-group-version-kind,omitempty"`
+
+	ref *Schema // Target of Ref, set when the document is linked
+}
+
+// Either a schema for the values of an object or whether other fields are allowed at all
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+type schemaGVK struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Follow $ref and single-element allOf wrappers, which Kubernetes uses to
+// attach a description to a referenced schema
+func (s *Schema) resolve() *Schema {
+	for range 32 { // Bound against reference cycles
+		switch {
+		case s.ref != nil:
+			s = s.ref
+		case len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0:
+			s = s.AllOf[0]
+		default:
+			return s
+		}
+	}
+	return s
+}
+
+// Schema of the named field of an object, nil if the object has no such field
+func (s *Schema) Field(name string) *Schema {
+	s = s.resolve()
+	if field, ok := s.Properties[name]; ok {
+		return field.resolve()
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		return s.AdditionalProperties.Schema.resolve()
+	}
+	return nil
+}
+
+// An OpenAPI v3 document as served for one group version
+type Document struct {
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	kinds map[schema.GroupVersionKind]*Schema
+}
+
+// Parse an OpenAPI v3 document, resolving references between its schemas
+func ParseDocument(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	doc.kinds = make(map[schema.GroupVersionKind]*Schema)
+	for _, s := range doc.Components.Schemas {
+		doc.link(s)
+		for _, gvk := range s.GroupVersionKinds {
+			doc.kinds[schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}] = s
+		}
+	}
+	return &doc, nil
+}
+
+// Schema of a kind the document describes, nil if it describes no such kind
+func (d *Document) Kind(gvk schema.GroupVersionKind) *Schema {
+	return d.kinds[gvk]
+}
+
+// Kinds the document describes
+func (d *Document) Kinds() []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0, len(d.kinds))
+	for gvk := range d.kinds {
+		kinds = append(kinds, gvk)
+	}
+	return kinds
+}
+
+func (d *Document) link(s *Schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		s.ref = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	for _, property := range s.Properties {
+		d.link(property)
+	}
+	if s.AdditionalProperties != nil {
+		d.link(s.AdditionalProperties.Schema)
+	}
+	d.link(s.Items)
+	for _, list := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, sub := range list {
+			d.link(sub)
+		}
+	}
+}
+
+// Schemas of the versions a CRD serves. Every custom resource has apiVersion,
+// kind and metadata, which schemas don't always spell out.
+func CRDSchemas(crd *apiextv1.CustomResourceDefinition) (map[schema.GroupVersionKind]*Schema, error) {
+	schemas := make(map[schema.GroupVersionKind]*Schema)
+	for _, version := range crd.Spec.Versions {
+		if !version.Served || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		data, err := json.Marshal(version.Schema.OpenAPIV3Schema)
+		if err != nil {
+			return nil, fmt.Errorf("CRD %s version %s: %w", crd.Name, version.Name, err)
+		}
+		var s Schema
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("CRD %s version %s: %w", crd.Name, version.Name, err)
+		}
+
+		if len(s.Properties) > 0 {
+			for _, name := range []string{"apiVersion", "kind"} {
+				if _, ok := s.Properties[name]; !ok {
+					s.Properties[name] = &Schema{Type: "string"}
+				}
+			}
+			if _, ok := s.Properties["metadata"]; !ok {
+				s.Properties["metadata"] = &Schema{Type: "object", PreserveUnknownFields: true}
+			}
+		}
+
+		schemas[schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}] = &s
+	}
+	return schemas, nil
+}
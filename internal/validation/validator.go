@@ -0,0 +1,495 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"kubeguide/internal/lint"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Problem found in a manifest, anchored at the node it is about
+type Diagnostic struct {
+	Line     int // 1-based
+	Column   int // 1-based
+	Severity Severity
+	Path     string // Field path, e.g. "spec.template.spec.containers[0].image", empty for the document
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Message)
+	}
+	return fmt.Sprintf("%d:%d %s: %s", d.Line, d.Column, d.Path, d.Message)
+}
+
+// Where schemas come from, e.g. a cluster or files on disk
+type SchemaSource interface {
+	// Schema of a kind, nil without error when the source doesn't know the kind
+	Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error)
+}
+
+// Checks manifests against OpenAPI schemas for unknown fields, wrong types,
+// missing required fields and values outside an enum
+type Validator struct {
+	sources []SchemaSource
+	linter  *lint.Linter // Nil to check schemas only
+}
+
+// Sources are asked in order, the first one knowing a kind wins
+func NewValidator(sources ...SchemaSource) *Validator {
+	return &Validator{sources: sources}
+}
+
+// Also report what the linter finds in each object, anchored at the field
+// the finding is about
+func (v *Validator) SetLinter(linter *lint.Linter) {
+	v.linter = linter
+}
+
+// Schema of a kind from the first source that knows it
+func (v *Validator) Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error) {
+	var errs []error
+	for _, source := range v.sources {
+		s, err := source.Schema(ctx, gvk)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if s != nil {
+			return s, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Line number in the messages of yaml syntax errors
+var yamlErrorLine = regexp.MustCompile(`^line (\d+): `)
+
+// Validate every document of a multi-document YAML manifest. Diagnostics are
+// sorted by position.
+func (v *Validator) Validate(ctx context.Context, data []byte) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// The decoder can't continue after a syntax error
+			line, message := 1, strings.TrimPrefix(err.Error(), "yaml: ")
+			if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+				line, _ = strconv.Atoi(match[1])
+				message = strings.TrimPrefix(message, match[0])
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Line: line, Column: 1, Severity: SeverityError, Message: message,
+			})
+			break
+		}
+
+		diagnostics = append(diagnostics, v.validateDocument(ctx, &document)...)
+	}
+
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return diagnostics
+}
+
+func (v *Validator) validateDocument(ctx context.Context, document *yaml.Node) []Diagnostic {
+	if len(document.Content) == 0 {
+		return nil // Empty document, e.g. after a trailing "---"
+	}
+	root := document.Content[0]
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil
+	}
+
+	w := &walker{}
+	if root.Kind != yaml.MappingNode {
+		w.add(root, "", "a manifest must be a mapping with apiVersion and kind")
+		return w.diagnostics
+	}
+
+	apiVersion, kind := mappingValue(root, "apiVersion"), mappingValue(root, "kind")
+	if apiVersion == nil || kind == nil {
+		w.add(root, "", "apiVersion and kind are required")
+		return w.diagnostics
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion.Value)
+	if err != nil {
+		w.add(apiVersion, "apiVersion", err.Error())
+		return w.diagnostics
+	}
+	gvk := gv.WithKind(kind.Value)
+
+	s, err := v.Schema(ctx, gvk)
+	switch {
+	case err != nil:
+		w.addWarning(kind, "kind", fmt.Sprintf("schema of %s unavailable, not validated: %v", kind.Value, err))
+	case s == nil:
+		w.addWarning(kind, "kind", fmt.Sprintf("no schema for %s in %s, not validated", kind.Value, apiVersion.Value))
+	default:
+		w.validate(root, nil, s, "")
+	}
+
+	if v.linter != nil {
+		w.lint(v.linter, root)
+	}
+	return w.diagnostics
+}
+
+type walker struct {
+	diagnostics []Diagnostic
+}
+
+func (w *walker) add(node *yaml.Node, path, message string) {
+	w.diagnostics = append(w.diagnostics, Diagnostic{
+		Line: node.Line, Column: node.Column, Severity: SeverityError, Path: path, Message: message,
+	})
+}
+
+func (w *walker) lint(linter *lint.Linter, root *yaml.Node) {
+	// Round trip for the JSON types unstructured objects hold, integers as int64
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return
+	}
+	var obj map[string]any
+	if err := utilyaml.Unmarshal(data, &obj); err != nil {
+		return
+	}
+
+	for _, finding := range linter.Lint(&unstructured.Unstructured{Object: obj}, nil) {
+		severity := SeverityInfo
+		switch finding.Severity {
+		case lint.SeverityError:
+			severity = SeverityError
+		case lint.SeverityWarning:
+			severity = SeverityWarning
+		}
+		node := nodeAtPath(root, finding.Path)
+		w.diagnostics = append(w.diagnostics, Diagnostic{
+			Line: node.Line, Column: node.Column, Severity: severity, Path: finding.Path,
+			Message: fmt.Sprintf("%s (%s)", finding.Message, finding.Rule),
+		})
+	}
+}
+
+func (w *walker) addWarning(node *yaml.Node, path, message string) {
+	w.diagnostics = append(w.diagnostics, Diagnostic{
+		Line: node.Line, Column: node.Column, Severity: SeverityWarning, Path: path, Message: message,
+	})
+}
+
+// Check node against s. key is the mapping key node holds the value of, nil
+// for the document root and list items; problems with the field as a whole,
+// like missing required fields, are reported there.
+func (w *walker) validate(node, key *yaml.Node, s *Schema, path string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	s = s.resolve()
+	anchor := node
+	if key != nil {
+		anchor = key
+	}
+
+	// Null leaves the field unset
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if s.IntOrString {
+		if !scalarMatches(node, "integer") && !scalarMatches(node, "string") {
+			w.add(anchor, path, fmt.Sprintf("expected integer or string, got %s", nodeType(node)))
+		}
+		return
+	}
+
+	// Alternatives, e.g. quantities that may be strings or numbers
+	if s.Type == "" && (len(s.OneOf) > 0 || len(s.AnyOf) > 0) {
+		alternatives := append(slices.Clone(s.OneOf), s.AnyOf...)
+		var firstProblems []Diagnostic
+		for i, alternative := range alternatives {
+			attempt := &walker{}
+			attempt.validate(node, key, alternative, path)
+			if len(attempt.diagnostics) == 0 {
+				return
+			}
+			if i == 0 {
+				firstProblems = attempt.diagnostics
+			}
+		}
+		w.diagnostics = append(w.diagnostics, firstProblems...)
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			w.add(anchor, path, fmt.Sprintf("expected object, got %s", nodeType(node)))
+			return
+		}
+		w.validateObject(node, anchor, s, path)
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			w.add(anchor, path, fmt.Sprintf("expected array, got %s", nodeType(node)))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range node.Content {
+				w.validate(item, nil, s.Items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case "string", "integer", "number", "boolean":
+		if !scalarMatches(node, s.Type) {
+			w.add(node, path, fmt.Sprintf("expected %s, got %s", s.Type, nodeType(node)))
+			return
+		}
+	case "":
+		// Any value, unless the schema still describes an object's fields
+		if node.Kind == yaml.MappingNode && len(s.Properties) > 0 {
+			w.validateObject(node, anchor, s, path)
+		}
+	}
+
+	if len(s.Enum) > 0 && node.Kind == yaml.ScalarNode {
+		allowed := make([]string, 0, len(s.Enum))
+		for _, value := range s.Enum {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+		if !slices.Contains(allowed, node.Value) {
+			w.add(node, path, fmt.Sprintf("unsupported value %q, must be one of: %s", node.Value, strings.Join(allowed, ", ")))
+		}
+	}
+}
+
+func (w *walker) validateObject(node, anchor *yaml.Node, s *Schema, path string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; isMergeKey(key) {
+			if _, ok := mergedMappings(node.Content[i+1]); !ok {
+				w.add(key, joinPath(path, key.Value), "a merge key needs a mapping or a list of mappings")
+			}
+		}
+	}
+
+	present := make(map[string]bool)
+	for _, f := range mappingFields(node) {
+		key, value := f.key, f.value
+		present[key.Value] = true
+		fieldPath := joinPath(path, key.Value)
+
+		if field, ok := s.Properties[key.Value]; ok {
+			w.validate(value, key, field, fieldPath)
+			continue
+		}
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			w.validate(value, key, s.AdditionalProperties.Schema, fieldPath)
+			continue
+		}
+
+		// Objects without listed fields are free-form, e.g. RawExtension
+		freeForm := len(s.Properties) == 0 || s.PreserveUnknownFields || s.EmbeddedResource ||
+			(s.AdditionalProperties != nil && s.AdditionalProperties.Allowed)
+		if !freeForm {
+			w.add(key, fieldPath, fmt.Sprintf("unknown field %q", key.Value))
+		}
+	}
+
+	for _, name := range s.Required {
+		if !present[name] {
+			w.add(anchor, path, fmt.Sprintf("missing required field %q", name))
+		}
+	}
+}
+
+// Node of a field path like "spec.containers[0].image", the key for fields
+// of objects. Where the path leads to a missing field, the deepest node on
+// the way stands in for it.
+func nodeAtPath(root *yaml.Node, path string) *yaml.Node {
+	node, anchor := root, root
+	if path == "" {
+		return anchor
+	}
+	for _, segment := range strings.Split(path, ".") {
+		field, indexes, _ := strings.Cut(segment, "[")
+		if node.Kind != yaml.MappingNode {
+			return anchor
+		}
+		found := false
+		for _, f := range mappingFields(node) {
+			if f.key.Value == field {
+				anchor, node = f.key, f.value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return anchor
+		}
+		if node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+
+		for _, index := range strings.Split(indexes, "[") {
+			if index == "" {
+				continue
+			}
+			i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+			if err != nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return anchor
+			}
+			node = node.Content[i]
+			anchor = node
+		}
+	}
+	return anchor
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for _, f := range mappingFields(node) {
+		if f.key.Value == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// Key and value of a mapping's field
+type field struct {
+	key, value *yaml.Node
+}
+
+// Fields of a mapping, including those pulled in from anchors with "<<"
+// merge keys. Fields set in the mapping win over merged ones, and earlier
+// mappings of a merged list over later ones.
+func mappingFields(node *yaml.Node) []field {
+	var fields, merged []field
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !isMergeKey(key) {
+			fields = append(fields, field{key, value})
+			continue
+		}
+		mappings, _ := mergedMappings(value)
+		for _, mapping := range mappings {
+			merged = append(merged, mappingFields(mapping)...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		seen[f.key.Value] = true
+	}
+	for _, f := range merged {
+		if !seen[f.key.Value] {
+			seen[f.key.Value] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Plain "<<", a quoted one is an ordinary key
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.Tag == "!!merge"
+}
+
+// Mappings the value of a merge key refers to, false unless it is a mapping
+// or a list of mappings
+func mergedMappings(value *yaml.Node) ([]*yaml.Node, bool) {
+	if value.Kind == yaml.AliasNode && value.Alias != nil {
+		value = value.Alias
+	}
+	switch value.Kind {
+	case yaml.MappingNode:
+		return []*yaml.Node{value}, true
+	case yaml.SequenceNode:
+		var mappings []*yaml.Node
+		for _, item := range value.Content {
+			if item.Kind == yaml.AliasNode && item.Alias != nil {
+				item = item.Alias
+			}
+			if item.Kind != yaml.MappingNode {
+				return nil, false
+			}
+			mappings = append(mappings, item)
+		}
+		return mappings, true
+	}
+	return nil, false
+}
+
+func scalarMatches(node *yaml.Node, schemaType string) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+	switch schemaType {
+	case "string":
+		// Dates and base64 get tags of their own, but are strings to the API
+		// server all the same
+		return node.Tag == "!!str" || node.Tag == "!!timestamp" || node.Tag == "!!binary"
+	case "integer":
+		return node.Tag == "!!int"
+	case "number":
+		return node.Tag == "!!int" || node.Tag == "!!float"
+	case "boolean":
+		return node.Tag == "!!bool"
+	}
+	return true
+}
+
+// Type of a node in the words of the schema
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
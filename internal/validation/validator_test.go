@@ -0,0 +1,283 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Schemas known up front, by kind
+type staticSchemas map[schema.GroupVersionKind]*Schema
+
+func (s staticSchemas) Schema(ctx context.Context, gvk schema.GroupVersionKind) (*Schema, error) {
+	return s[gvk], nil
+}
+
+const widgetSchema = `{
+	"type": "object",
+	"required": ["spec"],
+	"properties": {
+		"apiVersion": {"type": "string"},
+		"kind": {"type": "string"},
+		"metadata": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+			}
+		},
+		"spec": {
+			"type": "object",
+			"required": ["name", "image"],
+			"properties": {
+				"name": {"type": "string"},
+				"image": {"type": "string"},
+				"replicas": {"type": "integer"},
+				"port": {"x-kubernetes-int-or-string": true},
+				"mode": {"type": "string", "enum": ["fast", "safe"]},
+				"templates": {"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+				"env": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}, "value": {"type": "string"}}
+					}
+				}
+			}
+		}
+	}
+}`
+
+func newTestValidator(t *testing.T) *Validator {
+	t.Helper()
+	var s Schema
+	if err := json.Unmarshal([]byte(widgetSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	return NewValidator(staticSchemas{gvk: &s})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string // Diagnostic.String() of each diagnostic
+	}{
+		{
+			name: "valid",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: web
+  image: nginx
+  replicas: 2
+  port: http
+`,
+		},
+		{
+			name: "dates and binary are strings",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  annotations:
+    released: 2024-01-01
+    deployed-at: 2024-01-01T10:00:00Z
+    checksum: !!binary aGVsbG8=
+spec:
+  name: web
+  image: nginx
+  env:
+  - name: D
+    value: 2024-01-01
+`,
+		},
+		{
+			name: "wrong scalar types",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: 42
+  image: nginx
+  replicas: 2024-01-01
+  port: true
+`,
+			want: []string{
+				`4:9 spec.name: expected string, got integer`,
+				`6:13 spec.replicas: expected integer, got string`,
+				`7:3 spec.port: expected integer or string, got boolean`,
+			},
+		},
+		{
+			name: "missing required, unknown field and enum",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: web
+  colour: blue
+  mode: slow
+`,
+			want: []string{
+				`3:1 spec: missing required field "image"`,
+				`5:3 spec.colour: unknown field "colour"`,
+				`6:9 spec.mode: unsupported value "slow", must be one of: fast, safe`,
+			},
+		},
+		{
+			name: "required fields from a merge key",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  templates:
+    base: &base
+      name: web
+      image: nginx
+  <<: *base
+  replicas: 2
+`,
+		},
+		{
+			name: "required fields from a list of merged mappings",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  templates:
+    named: &named
+      name: web
+    imaged: &imaged
+      image: nginx
+  <<: [*named, *imaged]
+`,
+		},
+		{
+			name: "merged list item",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: web
+  image: nginx
+  env:
+  - &first
+    name: A
+    value: a
+  - <<: *first
+    value: b
+  - value: c
+`,
+			want: []string{
+				`12:5 spec.env[2]: missing required field "name"`,
+			},
+		},
+		{
+			name: "explicit fields win over merged ones",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  templates:
+    base: &base
+      name: web
+      image: nginx
+      replicas: many
+  <<: *base
+  replicas: 2
+`,
+		},
+		{
+			name: "merged fields are validated",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  templates:
+    base: &base
+      name: web
+      image: nginx
+      colour: blue
+  <<: *base
+`,
+			want: []string{
+				`8:7 spec.colour: unknown field "colour"`,
+			},
+		},
+		{
+			name: "merge key without a mapping",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: web
+  image: nginx
+  <<: nginx
+`,
+			want: []string{
+				`6:3 spec.<<: a merge key needs a mapping or a list of mappings`,
+			},
+		},
+		{
+			name: "quoted merge key is a field",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+spec:
+  name: web
+  image: nginx
+  "<<": {}
+`,
+			want: []string{
+				`6:3 spec.<<: unknown field "<<"`,
+			},
+		},
+	}
+	validator := newTestValidator(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, diagnostic := range validator.Validate(context.Background(), []byte(tt.manifest)) {
+				got = append(got, diagnostic.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("diagnostics:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeAtPath(t *testing.T) {
+	manifest := `apiVersion: example.com/v1
+kind: Widget
+spec:
+  templates:
+    base: &base
+      image: nginx
+      env:
+      - name: A
+  <<: *base
+  name: web
+`
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(manifest), &document); err != nil {
+		t.Fatal(err)
+	}
+	root := document.Content[0]
+
+	tests := []struct {
+		path         string
+		line, column int
+	}{
+		{"", 1, 1},
+		{"spec.name", 10, 3},
+		{"spec.image", 6, 7}, // Merged from the anchor
+		{"spec.env[0]", 8, 9},
+		{"spec.env[0].name", 8, 9},
+		{"spec.env[3]", 7, 7}, // Missing, the deepest node on the way
+		{"spec.replicas", 3, 1},
+	}
+	for _, tt := range tests {
+		node := nodeAtPath(root, tt.path)
+		if node.Line != tt.line || node.Column != tt.column {
+			t.Errorf("nodeAtPath(%q) = %d:%d, want %d:%d", tt.path, node.Line, node.Column, tt.line, tt.column)
+		}
+	}
+}